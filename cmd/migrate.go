@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"time"
+
 	"go-modules-api/config"
 	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/version"
 	"go-modules-api/utils"
 
 	"github.com/spf13/cobra"
@@ -15,7 +20,6 @@ func newMigrateCmd() *cobra.Command {
 		Short: "Run database migrations",
 		Long:  `This command runs all database migrations using GORM AutoMigrate.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.InitLogger()
 			log := utils.Logger.Named("migrations")
 
 			log.Info("Starting database migrations")
@@ -27,23 +31,39 @@ func newMigrateCmd() *cobra.Command {
 			config.ConnectDatabase()
 
 			// Run migrations
-			err := config.DB.AutoMigrate(
-				&models.HubClient{},
-				&models.Role{},
-				&models.Module{},
-				&models.ModulePermission{},
-				&models.EntityRegister{},
-				&models.ModuleEntityRegister{},
-				&models.Product{},
-				&models.EntityRegisterProduct{},
-				&models.MenuItem{},
-				&models.MenuItemPermission{},
-			)
+			err := config.DB.AutoMigrate(models.Migrated()...)
 			if err != nil {
 				log.Fatal("Migration failed", zap.Error(err))
 			}
 
-			log.Info("Database migration completed successfully!")
+			// Idempotency keys used to be unique across every caller
+			if config.DB.Migrator().HasIndex(&models.IdempotencyKey{}, "idx_idempotency_keys_key") {
+				if err := config.DB.Migrator().DropIndex(&models.IdempotencyKey{}, "idx_idempotency_keys_key"); err != nil {
+					log.Fatal("Failed to drop the global idempotency key index", zap.Error(err))
+				}
+			}
+
+			// Seed the super role, its flag can't be set through the API
+			if config.Env.RbacSuperRole != "" {
+				role, err := repositories.NewRoleRepository(config.DB).EnsureSuper(config.Env.RbacSuperRole)
+				if err != nil {
+					log.Fatal("Failed to seed the super role", zap.Error(err))
+				}
+				log.Info("Super role ready", zap.String("slug", role.Slug), zap.Uint("id", role.ID))
+			}
+
+			// Record the build that applied the migrations
+			build := version.Get()
+			err = repositories.NewSystemRepository(config.DB).RecordMigration(context.Background(), &models.SchemaMigration{
+				Version:   build.Version,
+				Commit:    build.Commit,
+				AppliedAt: time.Now(),
+			})
+			if err != nil {
+				log.Fatal("Failed to record the migration", zap.Error(err))
+			}
+
+			log.Info("Database migration completed successfully!", zap.String("version", build.Version))
 		},
 	}
 
@@ -24,4 +24,5 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(newMigrateCmd())
 	rootCmd.AddCommand(newSeedCmd())
+	rootCmd.AddCommand(newVersionCmd())
 }
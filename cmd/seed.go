@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"strconv"
 
 	"go-modules-api/config"
 	"go-modules-api/internal/factories"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/tenant"
 	"go-modules-api/utils"
 
 	"github.com/gofiber/fiber/v2/log"
@@ -31,7 +34,6 @@ func newSeedCmd() *cobra.Command {
 				numRecords = n
 			}
 
-			utils.InitLogger()
 			logger := utils.Logger.Named("seeds")
 
 			// Format the message before passing it to the logger
@@ -50,6 +52,11 @@ func newSeedCmd() *cobra.Command {
 
 				role := factories.RoleFactory()
 				config.DB.Create(role)
+
+				// Users are tenant-owned, they are created in the hub client of the context
+				user := factories.UserFactory()
+				user.Roles = []models.Role{*role}
+				config.DB.WithContext(tenant.WithHubClientID(context.Background(), hubClient.ID)).Create(user)
 			}
 
 			logger.Info("Database seeding completed successfully!")
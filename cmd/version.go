@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"go-modules-api/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version of the binary",
+		Long:  `This command prints the version, commit and build time of the binary and the Go version it was built with.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			info := version.Get()
+			modified := ""
+			if info.Modified {
+				modified = " (modified)"
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "go-modules-api %s\n", info.Version)
+			fmt.Fprintf(cmd.OutOrStdout(), "commit:     %s%s\n", info.Commit, modified)
+			fmt.Fprintf(cmd.OutOrStdout(), "built:      %s\n", info.BuildTime)
+			fmt.Fprintf(cmd.OutOrStdout(), "go version: %s\n", info.GoVersion)
+		},
+	}
+
+	return cmd
+}
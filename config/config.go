@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"go-modules-api/utils"
 
@@ -19,11 +20,103 @@ type Config struct {
 	AppPort     int    `envconfig:"APP_PORT" default:"3000"`
 	UsePublicIP bool   `envconfig:"USE_PUBLIC_IP" default:"false"`
 
+	LegacyErrorFormat bool   `envconfig:"LEGACY_ERROR_FORMAT" default:"false"`
+	HttpCacheControl  string `envconfig:"HTTP_CACHE_CONTROL" default:"private, no-cache"`
+
+	CorsAllowOrigins     []string `envconfig:"CORS_ALLOW_ORIGINS" default:"*"`
+	CorsAllowCredentials bool     `envconfig:"CORS_ALLOW_CREDENTIALS" default:"false"`
+
+	HttpBodyLimit             int           `envconfig:"HTTP_BODY_LIMIT" default:"4194304"`
+	HttpRouteBodyLimits       []string      `envconfig:"HTTP_ROUTE_BODY_LIMITS" default:""`
+	HttpReadTimeout           time.Duration `envconfig:"HTTP_READ_TIMEOUT" default:"15s"`
+	HttpWriteTimeout          time.Duration `envconfig:"HTTP_WRITE_TIMEOUT" default:"15s"`
+	HttpIdleTimeout           time.Duration `envconfig:"HTTP_IDLE_TIMEOUT" default:"60s"`
+	HttpHstsMaxAge            int           `envconfig:"HTTP_HSTS_MAX_AGE" default:"31536000"`
+	HttpFrameOptions          string        `envconfig:"HTTP_FRAME_OPTIONS" default:"DENY"`
+	HttpProxyHeader           string        `envconfig:"HTTP_PROXY_HEADER" default:""`
+	HttpTrustedProxies        []string      `envconfig:"HTTP_TRUSTED_PROXIES" default:""`
+	HttpContentSecurityPolicy string        `envconfig:"HTTP_CONTENT_SECURITY_POLICY" default:"default-src 'self'; script-src 'self' https://cdn.redoc.ly; style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; font-src 'self' https://fonts.gstatic.com; img-src 'self' data: https:; worker-src 'self' blob:; frame-ancestors 'none'"`
+
+	TLSCertFile       string        `envconfig:"TLS_CERT_FILE" default:""`
+	TLSKeyFile        string        `envconfig:"TLS_KEY_FILE" default:""`
+	TLSClientCAFile   string        `envconfig:"TLS_CLIENT_CA_FILE" default:""`
+	TLSClientAuth     string        `envconfig:"TLS_CLIENT_AUTH" default:"none"`
+	TLSReloadInterval time.Duration `envconfig:"TLS_RELOAD_INTERVAL" default:"30s"`
+
+	MetricsEnabled    bool     `envconfig:"METRICS_ENABLED" default:"true"`
+	MetricsToken      string   `envconfig:"METRICS_TOKEN" default:"" redact:"true"`
+	MetricsAllowedIPs []string `envconfig:"METRICS_ALLOWED_IPS" default:""`
+
+	LogLevel                   string   `envconfig:"LOG_LEVEL" default:"info"`
+	LogFormat                  string   `envconfig:"LOG_FORMAT" default:"console"`
+	LogOutputs                 []string `envconfig:"LOG_OUTPUTS" default:"stdout,file"`
+	LogFile                    string   `envconfig:"LOG_FILE" default:"logs/app.log"`
+	LogMaxSizeMB               int      `envconfig:"LOG_MAX_SIZE_MB" default:"10"`
+	LogMaxBackups              int      `envconfig:"LOG_MAX_BACKUPS" default:"3"`
+	LogMaxAgeDays              int      `envconfig:"LOG_MAX_AGE_DAYS" default:"7"`
+	LogCompress                bool     `envconfig:"LOG_COMPRESS" default:"true"`
+	LogRequestSampleInitial    int      `envconfig:"LOG_REQUEST_SAMPLE_INITIAL" default:"100"`
+	LogRequestSampleThereafter int      `envconfig:"LOG_REQUEST_SAMPLE_THEREAFTER" default:"100"`
+
+	TracingEnabled     bool    `envconfig:"TRACING_ENABLED" default:"false"`
+	TracingEndpoint    string  `envconfig:"TRACING_ENDPOINT" default:"http://localhost:4318"`
+	TracingServiceName string  `envconfig:"TRACING_SERVICE_NAME" default:"go-modules-api"`
+	TracingSampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+
+	PprofEnabled              bool   `envconfig:"PPROF_ENABLED" default:"false"`
+	PprofAddr                 string `envconfig:"PPROF_ADDR" default:""`
+	PprofBlockProfileRate     int    `envconfig:"PPROF_BLOCK_PROFILE_RATE" default:"10000"`
+	PprofMutexProfileFraction int    `envconfig:"PPROF_MUTEX_PROFILE_FRACTION" default:"100"`
+
+	HealthCheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	ShutdownDelay   time.Duration `envconfig:"SHUTDOWN_DELAY" default:"5s"`
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
+
+	IdempotencyTTL             time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	IdempotencyCleanupInterval time.Duration `envconfig:"IDEMPOTENCY_CLEANUP_INTERVAL" default:"1h"`
+
+	AuthEnabled      bool     `envconfig:"AUTH_ENABLED" default:"true"`
+	AuthPublicRoutes []string `envconfig:"AUTH_PUBLIC_ROUTES" default:""`
+
+	RateLimitEnabled bool              `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	RateLimits       map[string]string `envconfig:"RATE_LIMITS" default:"default.api_key:600/1m,default.hub_client:1200/1m,default.ip:300/1m,auth.ip:10/1m,auth.client_id:10/1m"`
+
+	RbacEnabled   bool   `envconfig:"RBAC_ENABLED" default:"true"`
+	RbacSuperRole string `envconfig:"RBAC_SUPER_ROLE" default:"admin"`
+
+	JwtAlgorithm      string        `envconfig:"JWT_ALGORITHM" default:"HS256"`
+	JwtSecret         string        `envconfig:"JWT_SECRET" default:"" redact:"true"`
+	JwtPrivateKeyFile string        `envconfig:"JWT_PRIVATE_KEY_FILE" default:""`
+	JwtPublicKeyFile  string        `envconfig:"JWT_PUBLIC_KEY_FILE" default:""`
+	JwtJwksFile       string        `envconfig:"JWT_JWKS_FILE" default:""`
+	JwtKeyID          string        `envconfig:"JWT_KEY_ID" default:""`
+	JwtIssuer         string        `envconfig:"JWT_ISSUER" default:"go-modules-api"`
+	JwtAudience       string        `envconfig:"JWT_AUDIENCE" default:""`
+	JwtAccessTTL      time.Duration `envconfig:"JWT_ACCESS_TTL" default:"15m"`
+	JwtRefreshTTL     time.Duration `envconfig:"JWT_REFRESH_TTL" default:"168h"`
+
+	PasswordResetTTL time.Duration `envconfig:"PASSWORD_RESET_TTL" default:"1h"`
+	PasswordResetURL string        `envconfig:"PASSWORD_RESET_URL" default:""`
+
+	SmtpHost     string `envconfig:"SMTP_HOST" default:""`
+	SmtpPort     int    `envconfig:"SMTP_PORT" default:"587"`
+	SmtpUsername string `envconfig:"SMTP_USERNAME" default:""`
+	SmtpPassword string `envconfig:"SMTP_PASSWORD" default:"" redact:"true"`
+	SmtpFrom     string `envconfig:"SMTP_FROM" default:""`
+
+	RevocationCleanupInterval time.Duration `envconfig:"REVOCATION_CLEANUP_INTERVAL" default:"1h"`
+
+	OAuthTokenTTL             time.Duration `envconfig:"OAUTH_TOKEN_TTL" default:"15m"`
+	OAuthTokenCleanupInterval time.Duration `envconfig:"OAUTH_TOKEN_CLEANUP_INTERVAL" default:"1h"`
+
 	DbHost string `envconfig:"DB_HOST" default:"localhost"`
 	DbPort string `envconfig:"DB_PORT" default:"5432"`
 	DbUser string `envconfig:"DB_USER" default:"postgres"`
-	DbPass string `envconfig:"DB_PASSWORD" default:"postgres"`
+	DbPass string `envconfig:"DB_PASSWORD" default:"postgres" redact:"true"`
 	DbName string `envconfig:"DB_NAME" default:"postgres"`
+
+	DbSlowQueryThreshold time.Duration `envconfig:"DB_SLOW_QUERY_THRESHOLD" default:"200ms"`
 }
 
 var Env = &Config{}
@@ -43,7 +136,11 @@ func (c *Config) loadEnvFile(log *zap.Logger) {
 
 func (c *Config) configureAppHost(ip string, ipBlocked bool, log *zap.Logger) {
 	if c.AppHost == "" {
-		c.AppHost = "http://" + ip + ":" + strconv.Itoa(c.AppPort)
+		scheme := "http://"
+		if c.TLSEnabled() {
+			scheme = "https://"
+		}
+		c.AppHost = scheme + ip + ":" + strconv.Itoa(c.AppPort)
 
 		if c.UsePublicIP {
 			if ipBlocked {
@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 
+	"go-modules-api/internal/metrics"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
 	"go-modules-api/utils"
 
 	"go.uber.org/zap"
@@ -36,12 +39,43 @@ func ConnectDatabase() {
 			SingularTable: false,
 		},
 		TranslateError: true,
+		Logger:         utils.NewGormLogger(log, Env.DbSlowQueryThreshold),
 	})
 	if err != nil {
 		log.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
+	// Scope every tenant-owned model to the hub client of the statement context
+	if err := tenant.RegisterCallbacks(database); err != nil {
+		log.Fatal("Failed to register tenant callbacks", zap.Error(err))
+	}
+
+	// Time every statement and expose the connection pool statistics
+	if err := metrics.RegisterCallbacks(database, Env.DbSlowQueryThreshold); err != nil {
+		log.Fatal("Failed to register metrics callbacks", zap.Error(err))
+	}
+	if err := metrics.RegisterDBStats(database, Env.DbName); err != nil {
+		log.Fatal("Failed to register database metrics", zap.Error(err))
+	}
+
+	// Trace every statement as a child of the span of its request
+	if err := tracing.RegisterCallbacks(database); err != nil {
+		log.Fatal("Failed to register tracing callbacks", zap.Error(err))
+	}
+
 	DB = database
 	log.Info("Database connection established successfully!")
 
 }
+
+// CloseDatabase closes the connection pool once nothing uses the database anymore
+func CloseDatabase() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
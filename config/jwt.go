@@ -0,0 +1,173 @@
+package config
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"go-modules-api/utils"
+
+	"github.com/goccy/go-json"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// JWTKeys holds the key material used to sign and verify JWTs
+type JWTKeys struct {
+	// SigningMethod is the algorithm used for issued tokens
+	SigningMethod jwt.SigningMethod
+	// SigningKey is a []byte secret for HS256 or an *rsa.PrivateKey for RS256, nil when tokens can only be verified
+	SigningKey interface{}
+	// SigningKeyID is sent as the kid header of issued tokens
+	SigningKeyID string
+	// VerifyKeys maps a kid to its verification key, the empty kid holds the default key
+	VerifyKeys map[string]interface{}
+}
+
+// JWT is the global JWT key material
+var JWT *JWTKeys
+
+// minJWTSecretLength is the shortest HS256 secret accepted, matching the 256 bits of the hash
+const minJWTSecretLength = 32
+
+// LoadJWTKeys loads the JWT keys configured in Env
+func LoadJWTKeys() {
+	log := utils.Logger.Named("jwt")
+
+	keys, err := Env.loadJWTKeys()
+	if err != nil {
+		log.Fatal("Failed to load JWT keys", zap.Error(err))
+	}
+
+	JWT = keys
+	log.Info("JWT keys loaded", zap.String("algorithm", Env.JwtAlgorithm), zap.Int("verify_keys", len(keys.VerifyKeys)))
+}
+
+func (c *Config) loadJWTKeys() (*JWTKeys, error) {
+	keys := &JWTKeys{
+		SigningKeyID: c.JwtKeyID,
+		VerifyKeys:   map[string]interface{}{},
+	}
+
+	switch c.JwtAlgorithm {
+	case "HS256":
+		keys.SigningMethod = jwt.SigningMethodHS256
+		if c.JwtSecret != "" {
+			if len(c.JwtSecret) < minJWTSecretLength {
+				return nil, fmt.Errorf("JWT_SECRET must be at least %d bytes long", minJWTSecretLength)
+			}
+			keys.SigningKey = []byte(c.JwtSecret)
+			keys.VerifyKeys[""] = []byte(c.JwtSecret)
+		}
+
+	case "RS256":
+		keys.SigningMethod = jwt.SigningMethodRS256
+		if c.JwtPrivateKeyFile != "" {
+			pem, err := os.ReadFile(filepath.Clean(c.JwtPrivateKeyFile))
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JWT private key: %w", err)
+			}
+			keys.SigningKey = privateKey
+			keys.VerifyKeys[""] = &privateKey.PublicKey
+		}
+		if c.JwtPublicKeyFile != "" {
+			pem, err := os.ReadFile(filepath.Clean(c.JwtPublicKeyFile))
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JWT public key: %w", err)
+			}
+			keys.VerifyKeys[""] = publicKey
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, use HS256 or RS256", c.JwtAlgorithm)
+	}
+
+	if c.JwtJwksFile != "" {
+		if err := loadJWKS(c.JwtJwksFile, keys.VerifyKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(keys.VerifyKeys) == 0 {
+		return nil, errors.New("no JWT verification key configured, set JWT_SECRET, JWT_PUBLIC_KEY_FILE or JWT_JWKS_FILE")
+	}
+
+	if keys.SigningKeyID != "" && keys.SigningKey != nil {
+		if _, ok := keys.VerifyKeys[keys.SigningKeyID]; !ok {
+			keys.VerifyKeys[keys.SigningKeyID] = keys.VerifyKeys[""]
+		}
+	}
+
+	return keys, nil
+}
+
+// jwk is a single entry of a JSON Web Key Set
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// loadJWKS adds the RSA and symmetric signature keys of a JWKS file to keys, indexed by kid
+func loadJWKS(path string, keys map[string]interface{}) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("invalid JWKS file: %w", err)
+	}
+
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return fmt.Errorf("invalid JWKS key %q: %w", key.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return fmt.Errorf("invalid JWKS key %q: %w", key.Kid, err)
+			}
+			keys[key.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return fmt.Errorf("invalid JWKS key %q: %w", key.Kid, err)
+			}
+			if len(secret) < minJWTSecretLength {
+				return fmt.Errorf("invalid JWKS key %q: symmetric keys must be at least %d bytes long", key.Kid, minJWTSecretLength)
+			}
+			keys[key.Kid] = secret
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package config
+
+import "go-modules-api/utils"
+
+// LogConfig returns the logger configuration of the environment
+func (c *Config) LogConfig() utils.LogConfig {
+	return utils.LogConfig{
+		Level:      c.LogLevel,
+		Format:     c.LogFormat,
+		Outputs:    c.LogOutputs,
+		File:       c.LogFile,
+		MaxSizeMB:  c.LogMaxSizeMB,
+		MaxBackups: c.LogMaxBackups,
+		MaxAgeDays: c.LogMaxAgeDays,
+		Compress:   c.LogCompress,
+	}
+}
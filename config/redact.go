@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redacted replaces the value of a sensitive setting that is set
+const redacted = "[REDACTED]"
+
+// sensitiveSuffixes mark settings that are redacted even when their field lacks the redact tag
+var sensitiveSuffixes = []string{"_PASSWORD", "_SECRET", "_TOKEN"}
+
+// Redacted returns the effective configuration keyed by environment variable, safe to expose to
+// operators. Settings tagged redact:"true", or named like a secret, only show whether they are set.
+func (c *Config) Redacted() map[string]interface{} {
+	settings := map[string]interface{}{}
+
+	value := reflect.ValueOf(c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := field.Tag.Get("envconfig")
+		if name == "" {
+			continue
+		}
+
+		setting := value.Field(i)
+		switch {
+		case sensitive(field) && setting.IsZero():
+			settings[name] = ""
+		case sensitive(field):
+			settings[name] = redacted
+		case field.Type == reflect.TypeFor[time.Duration]():
+			settings[name] = time.Duration(setting.Int()).String()
+		default:
+			settings[name] = setting.Interface()
+		}
+	}
+	return settings
+}
+
+// sensitive reports whether the setting of the field must not be exposed
+func sensitive(field reflect.StructField) bool {
+	if field.Tag.Get("redact") == "true" {
+		return true
+	}
+	name := field.Tag.Get("envconfig")
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
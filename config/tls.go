@@ -0,0 +1,177 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TLSEnabled reports whether the server certificate and key are configured
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// TLSFiles holds the server certificate and the client CA bundle loaded from their files.
+// Handshakes always use the latest files, so they can be replaced without a restart.
+type TLSFiles struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// NewTLSFiles loads the files configured in Env
+func NewTLSFiles() (*TLSFiles, error) {
+	clientAuth, err := clientAuthType(Env.TLSClientAuth, Env.TLSClientCAFile != "")
+	if err != nil {
+		return nil, err
+	}
+
+	files := &TLSFiles{
+		certFile:   Env.TLSCertFile,
+		keyFile:    Env.TLSKeyFile,
+		caFile:     Env.TLSClientCAFile,
+		clientAuth: clientAuth,
+	}
+	if err := files.load(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Config returns a TLS configuration reading the certificate and client CAs of the latest files
+// on every handshake
+func (f *TLSFiles) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			f.mu.RLock()
+			defer f.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*f.certificate},
+				ClientAuth:   f.clientAuth,
+				ClientCAs:    f.clientCAs,
+			}, nil
+		},
+	}
+}
+
+// Reload loads the files again when one of them changed, reporting whether they did.
+// The previous files stay in use when the new ones are invalid.
+func (f *TLSFiles) Reload() (bool, error) {
+	f.mu.RLock()
+	changed := false
+	for path, modTime := range f.modTimes {
+		info, err := os.Stat(path)
+		if err != nil {
+			f.mu.RUnlock()
+			return false, err
+		}
+		if !info.ModTime().Equal(modTime) {
+			changed = true
+		}
+	}
+	f.mu.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+	return true, f.load()
+}
+
+// Watch reloads the files every interval until done is closed
+func (f *TLSFiles) Watch(interval time.Duration, done <-chan struct{}, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			reloaded, err := f.Reload()
+			if err != nil {
+				log.Error("Failed to reload TLS files, keeping the previous ones", zap.Error(err))
+				continue
+			}
+			if reloaded {
+				log.Info("TLS files reloaded")
+			}
+		}
+	}
+}
+
+// load reads every file and swaps them in once all of them are valid
+func (f *TLSFiles) load() error {
+	modTimes := map[string]time.Time{}
+	for _, path := range []string{f.certFile, f.keyFile, f.caFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	certificate, err := tls.LoadX509KeyPair(filepath.Clean(f.certFile), filepath.Clean(f.keyFile))
+	if err != nil {
+		return fmt.Errorf("invalid TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if f.caFile != "" {
+		pem, err := os.ReadFile(filepath.Clean(f.caFile))
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("invalid TLS client CA bundle, no PEM certificate found")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.certificate = &certificate
+	f.clientCAs = clientCAs
+	f.modTimes = modTimes
+	return nil
+}
+
+// clientAuthType maps TLS_CLIENT_AUTH to the client certificate policy. Certificates are verified
+// against the CA bundle when one is set; otherwise they are only requested, so they can be pinned
+// by fingerprint.
+func clientAuthType(mode string, verify bool) (tls.ClientAuthType, error) {
+	switch mode {
+	case "none", "":
+		return tls.NoClientCert, nil
+	case "optional":
+		if verify {
+			return tls.VerifyClientCertIfGiven, nil
+		}
+		return tls.RequestClientCert, nil
+	case "require":
+		if verify {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+		return tls.RequireAnyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unsupported TLS_CLIENT_AUTH %q, use none, optional or require", mode)
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"go-modules-api/internal/version"
+)
+
+type LogLevelDTO struct {
+	Level string `json:"level" validate:"required,oneof=debug info warn error"`
+}
+
+// SystemInfoDTO describes the running process for operators
+type SystemInfoDTO struct {
+	Build         version.Info           `json:"build"`
+	StartedAt     time.Time              `json:"started_at"`
+	Uptime        string                 `json:"uptime"`
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+	Config        map[string]interface{} `json:"config"`
+	Database      DatabaseInfoDTO        `json:"database"`
+	Routes        []RouteInfoDTO         `json:"routes"`
+}
+
+// DatabaseInfoDTO describes the database server and the migration applied to it.
+// Error is set instead of failing the whole report when the database can't be queried.
+type DatabaseInfoDTO struct {
+	ServerVersion string            `json:"server_version,omitempty"`
+	Migration     *MigrationInfoDTO `json:"migration"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// MigrationInfoDTO describes the latest run of the migrate command
+type MigrationInfoDTO struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+type RouteInfoDTO struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Name   string `json:"name,omitempty"`
+}
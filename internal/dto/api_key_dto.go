@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyDTO struct {
+	Name      string     `json:"name" validate:"required,min=3,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,scope"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+type CreateClientCertificateDTO struct {
+	Name        string     `json:"name" validate:"required,min=3,max=100"`
+	Fingerprint string     `json:"fingerprint" validate:"required_without=Subject,omitempty,fingerprint"`
+	Subject     string     `json:"subject" validate:"required_without=Fingerprint,omitempty,max=255"`
+	Scopes      []string   `json:"scopes" validate:"required,min=1,dive,scope"`
+	ExpiresAt   *time.Time `json:"expires_at" validate:"omitempty"`
+}
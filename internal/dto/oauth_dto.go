@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type CreateOAuthClientSecretDTO struct {
+	Name      string     `json:"name" validate:"required,min=3,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,scope"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+}
+
+// OAuthClientDTO carries the client credentials sent in the body of an OAuth2 request
+type OAuthClientDTO struct {
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+}
+
+type OAuthTokenRequestDTO struct {
+	OAuthClientDTO
+	GrantType string `json:"grant_type" form:"grant_type"`
+	Scope     string `json:"scope" form:"scope"`
+}
+
+type OAuthTokenActionDTO struct {
+	OAuthClientDTO
+	Token         string `json:"token" form:"token"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint"`
+}
+
+type OAuthTokenDTO struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+type OAuthIntrospectionDTO struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+// ListDTO holds the filtering and sorting options shared by every list endpoint.
+// Allowed sort fields are resource specific and checked by the handler.
+type ListDTO struct {
+	Search    string `json:"search" validate:"omitempty"`
+	Active    *bool  `json:"active" validate:"omitempty"`
+	SortField string `json:"sort_field" validate:"omitempty"`
+	SortOrder string `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+}
+
+// PaginatedDTO holds the paging, filtering and sorting options shared by every paginate endpoint.
+type PaginatedDTO struct {
+	Page      int    `json:"page" validate:"omitempty,min=1"`
+	PageSize  int    `json:"page_size" validate:"omitempty,min=1,max=100"`
+	Search    string `json:"search" validate:"omitempty"`
+	Active    *bool  `json:"active" validate:"omitempty"`
+	SortField string `json:"sort_field" validate:"omitempty"`
+	SortOrder string `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+type CreateUserDTO struct {
+	Name     string `json:"name" validate:"required,min=3,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	RoleIDs  []uint `json:"role_ids" validate:"omitempty,dive,gt=0"`
+}
+
+type UpdateUserDTO struct {
+	Name    string  `json:"name" validate:"omitempty,min=3,max=100"`
+	Email   string  `json:"email" validate:"omitempty,email,max=255"`
+	RoleIDs *[]uint `json:"role_ids" validate:"omitempty,dive,gt=0"`
+}
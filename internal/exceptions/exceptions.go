@@ -3,16 +3,39 @@ package exceptions
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// ProblemContentType is the media type of RFC 7807 error responses
+const ProblemContentType = "application/problem+json"
+
+// LegacyFormat makes Response emit the pre RFC 7807 error shape.
+// It is set from the LEGACY_ERROR_FORMAT config while clients migrate.
+var LegacyFormat = false
+
 // APIException represents a structured API error response
 type APIException struct {
 	Status  int         `json:"status"`
 	Code    string      `json:"code"`
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"`
+
+	// RequestID identifies the failed request in the logs. It is set on a copy while rendering.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Problem represents an RFC 7807 problem details response
+type Problem struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Status    int           `json:"status"`
+	Detail    string        `json:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	Code      string        `json:"code"`
+	RequestID string        `json:"request_id,omitempty"`
+	Errors    []interface{} `json:"errors"`
 }
 
 // Error implements the error interface
@@ -30,15 +53,79 @@ func NewAPIException(status int, code, message string, details interface{}) *API
 	}
 }
 
-// Response returns a structured JSON response
+// Problem converts the exception into RFC 7807 problem details for the given request path
+func (e *APIException) Problem(instance string) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Errors:   e.errors(),
+	}
+}
+
+// errors returns the details as a list, wrapping a single value when needed
+func (e *APIException) errors() []interface{} {
+	if e.Details == nil {
+		return []interface{}{}
+	}
+
+	value := reflect.ValueOf(e.Details)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []interface{}{e.Details}
+	}
+
+	errs := make([]interface{}, value.Len())
+	for i := range errs {
+		errs[i] = value.Index(i).Interface()
+	}
+	return errs
+}
+
+// Response returns a structured JSON response, carrying the X-Request-ID of the response when set
 func (e *APIException) Response(c *fiber.Ctx) error {
-	return c.Status(e.Status).JSON(e)
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+	if LegacyFormat {
+		return e.legacyResponse(c, requestID)
+	}
+	problem := e.Problem(c.OriginalURL())
+	problem.RequestID = requestID
+	return c.Status(e.Status).JSON(problem, ProblemContentType)
 }
 
+// legacyResponse returns the error shape used before problem details were introduced
+func (e *APIException) legacyResponse(c *fiber.Ctx, requestID string) error {
+	if e.Code == validationFailedCode {
+		body := fiber.Map{
+			"error":   e.Message,
+			"details": e.Details,
+		}
+		if requestID != "" {
+			body["request_id"] = requestID
+		}
+		return c.Status(e.Status).JSON(body)
+	}
+	exception := *e
+	exception.RequestID = requestID
+	return c.Status(e.Status).JSON(exception)
+}
+
+const validationFailedCode = "validation_failed"
+
 func BadRequest(message string, details interface{}) *APIException {
 	return NewAPIException(http.StatusBadRequest, "bad_request", message, details)
 }
 
+func Unauthorized(message string, details interface{}) *APIException {
+	return NewAPIException(http.StatusUnauthorized, "unauthorized", message, details)
+}
+
+func Forbidden(message string, details interface{}) *APIException {
+	return NewAPIException(http.StatusForbidden, "forbidden", message, details)
+}
+
 func NotFound(message string, details interface{}) *APIException {
 	return NewAPIException(http.StatusNotFound, "not_found", message, details)
 }
@@ -50,3 +137,11 @@ func InternalServerError(message string, details interface{}) *APIException {
 func Conflict(message string, details interface{}) *APIException {
 	return NewAPIException(http.StatusConflict, "duplicate_entry", message, details)
 }
+
+func TooManyRequests(message string, details interface{}) *APIException {
+	return NewAPIException(http.StatusTooManyRequests, "rate_limited", message, details)
+}
+
+func ValidationFailed(details interface{}) *APIException {
+	return NewAPIException(http.StatusUnprocessableEntity, validationFailedCode, "Validation failed", details)
+}
@@ -0,0 +1,126 @@
+package exceptions_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/exceptions"
+)
+
+func TestAPIException_Problem(t *testing.T) {
+	tests := []struct {
+		name      string
+		exception *exceptions.APIException
+		want      *exceptions.Problem
+	}{
+		{
+			name:      "without details",
+			exception: exceptions.NotFound("Role not found", nil),
+			want: &exceptions.Problem{
+				Type: "about:blank", Title: "Not Found", Status: 404, Detail: "Role not found",
+				Instance: "/api/roles/3", Code: "not_found", Errors: []interface{}{},
+			},
+		},
+		{
+			name:      "single detail is wrapped",
+			exception: exceptions.BadRequest("Invalid ID", fiber.Map{"field": "id"}),
+			want: &exceptions.Problem{
+				Type: "about:blank", Title: "Bad Request", Status: 400, Detail: "Invalid ID",
+				Instance: "/api/roles/3", Code: "bad_request", Errors: []interface{}{fiber.Map{"field": "id"}},
+			},
+		},
+		{
+			name:      "list of details",
+			exception: exceptions.ValidationFailed([]map[string]string{{"field": "name"}, {"field": "slug"}}),
+			want: &exceptions.Problem{
+				Type: "about:blank", Title: "Unprocessable Entity", Status: 422, Detail: "Validation failed",
+				Instance: "/api/roles/3", Code: "validation_failed",
+				Errors: []interface{}{map[string]string{"field": "name"}, map[string]string{"field": "slug"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.exception.Problem("/api/roles/3"))
+		})
+	}
+}
+
+func TestAPIException_Response(t *testing.T) {
+	tests := []struct {
+		name        string
+		legacy      bool
+		exception   *exceptions.APIException
+		contentType string
+		want        string
+	}{
+		{
+			name:        "problem details",
+			exception:   exceptions.Forbidden("Cannot act for another hub client", fiber.Map{"field": "X-Hub-Client"}),
+			contentType: exceptions.ProblemContentType,
+			want: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"Cannot act for another hub client",
+				"instance":"/api/roles?page=2","code":"forbidden","request_id":"req-1","errors":[{"field":"X-Hub-Client"}]}`,
+		},
+		{
+			name:        "problem details without details",
+			exception:   exceptions.Unauthorized("Invalid or expired token", nil),
+			contentType: exceptions.ProblemContentType,
+			want: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"Invalid or expired token",
+				"instance":"/api/roles?page=2","code":"unauthorized","request_id":"req-1","errors":[]}`,
+		},
+		{
+			name:        "legacy",
+			legacy:      true,
+			exception:   exceptions.Forbidden("Cannot act for another hub client", fiber.Map{"field": "X-Hub-Client"}),
+			contentType: fiber.MIMEApplicationJSON,
+			want: `{"status":403,"code":"forbidden","message":"Cannot act for another hub client",
+				"details":{"field":"X-Hub-Client"},"request_id":"req-1"}`,
+		},
+		{
+			name:        "legacy validation errors",
+			legacy:      true,
+			exception:   exceptions.ValidationFailed([]fiber.Map{{"field": "name"}}),
+			contentType: fiber.MIMEApplicationJSON,
+			want:        `{"error":"Validation failed","details":[{"field":"name"}],"request_id":"req-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exceptions.LegacyFormat = tt.legacy
+			defer func() { exceptions.LegacyFormat = false }()
+
+			app := fiber.New()
+			app.Get("/api/roles", func(c *fiber.Ctx) error {
+				c.Set(fiber.HeaderXRequestID, "req-1")
+				return tt.exception.Response(c)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/roles?page=2", nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exception.Status, resp.StatusCode)
+			assert.Equal(t, tt.contentType, resp.Header.Get(fiber.HeaderContentType))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(body))
+		})
+	}
+}
+
+func TestAPIException_Error(t *testing.T) {
+	err := exceptions.TooManyRequests("Too many requests, retry later", nil)
+	assert.Equal(t, "[429] rate_limited: Too many requests, retry later", err.Error())
+
+	// Exceptions marshal to the legacy shape when not rendered by Response
+	body, jsonErr := json.Marshal(err)
+	require.NoError(t, jsonErr)
+	assert.JSONEq(t, `{"status":429,"code":"rate_limited","message":"Too many requests, retry later"}`, string(body))
+}
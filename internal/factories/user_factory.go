@@ -0,0 +1,21 @@
+package factories
+
+import (
+	"go-modules-api/internal/models"
+	"go-modules-api/utils"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// DefaultUserPassword is the password of every seeded user
+const DefaultUserPassword = "password"
+
+// UserFactory creates a fake User instance
+func UserFactory() *models.User {
+	password, _ := utils.HashPassword(DefaultUserPassword)
+	return &models.User{
+		Name:     gofakeit.Name(),
+		Email:    gofakeit.Email(),
+		Password: password,
+	}
+}
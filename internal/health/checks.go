@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go-modules-api/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Database checks that the database accepts connections
+func Database(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Migrations checks that the table and columns of every model exist, which fails while the
+// migrate command has not run for the deployed models. Migrations are not rolled back under a
+// running server, so the check stops querying once it passed.
+func Migrations(db *gorm.DB, models []interface{}) Check {
+	var migrated atomic.Bool
+
+	return func(ctx context.Context) error {
+		if migrated.Load() {
+			return nil
+		}
+
+		schemas := make([]*schema.Schema, 0, len(models))
+		tables := make([]string, 0, len(models))
+		for _, model := range models {
+			statement := &gorm.Statement{DB: db}
+			if err := statement.Parse(model); err != nil {
+				return err
+			}
+			schemas = append(schemas, statement.Schema)
+			tables = append(tables, statement.Schema.Table)
+		}
+
+		var columns []struct {
+			TableName  string
+			ColumnName string
+		}
+		err := db.WithContext(tenant.WithoutScope(ctx)).
+			Raw("SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name IN ?", tables).
+			Scan(&columns).Error
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]map[string]bool, len(tables))
+		for _, column := range columns {
+			if existing[column.TableName] == nil {
+				existing[column.TableName] = map[string]bool{}
+			}
+			existing[column.TableName][column.ColumnName] = true
+		}
+		for _, modelSchema := range schemas {
+			if existing[modelSchema.Table] == nil {
+				return fmt.Errorf("pending migration, table %s does not exist", modelSchema.Table)
+			}
+			for _, name := range modelSchema.DBNames {
+				if !existing[modelSchema.Table][name] {
+					return fmt.Errorf("pending migration, column %s.%s does not exist", modelSchema.Table, name)
+				}
+			}
+		}
+
+		migrated.Store(true)
+		return nil
+	}
+}
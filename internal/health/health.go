@@ -0,0 +1,135 @@
+// Package health runs the readiness checks of the API and reports their status and latency
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports whether a subsystem is ready to serve requests
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a single check
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of every registered check
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Up reports whether every check passed
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Registry holds the readiness checks. Subsystems register their own checks, so readiness
+// covers every dependency without the handler knowing about them.
+type Registry struct {
+	timeout time.Duration
+
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// errDraining fails readiness once the server started shutting down
+var errDraining = errors.New("server is shutting down")
+
+// NewRegistry creates a registry whose checks fail when they take longer than timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout, checks: map[string]Check{}}
+}
+
+// Register adds a check, replacing any previous check of the same name
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Drain fails every following run, so load balancers stop routing requests to a server that is
+// shutting down while it finishes the ones in flight
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Run runs every check concurrently and reports down when any of them fails
+func (r *Registry) Run(ctx context.Context) Report {
+	if r.draining.Load() {
+		return Report{
+			Status: StatusDown,
+			Checks: map[string]Result{"shutdown": {Status: StatusDown, Error: errDraining.Error()}},
+		}
+	}
+
+	r.mu.RLock()
+	checks := make(map[string]Check, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run times a check, failing it when the context ends first so a hung dependency cannot
+// hold the probe
+func run(ctx context.Context, check Check) Result {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{Status: StatusUp, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestRegistry_Run_AllUp(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("a", func(ctx context.Context) error { return nil })
+	registry.Register("b", func(ctx context.Context) error { return nil })
+
+	report := registry.Run(context.Background())
+
+	assert.True(t, report.Up())
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusUp, report.Checks["a"].Status)
+	assert.Empty(t, report.Checks["a"].Error)
+}
+
+func TestRegistry_Run_FailingCheck(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("a", func(ctx context.Context) error { return nil })
+	registry.Register("b", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report := registry.Run(context.Background())
+
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["a"].Status)
+	assert.Equal(t, StatusDown, report.Checks["b"].Status)
+	assert.Equal(t, "connection refused", report.Checks["b"].Error)
+}
+
+func TestRegistry_Run_TimesOutHungCheck(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	registry := NewRegistry(20 * time.Millisecond)
+	registry.Register("hung", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	report := registry.Run(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Up())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["hung"].Error)
+}
+
+func TestRegistry_Register_ReplacesCheck(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("a", func(ctx context.Context) error { return errors.New("down") })
+	registry.Register("a", func(ctx context.Context) error { return nil })
+
+	assert.True(t, registry.Run(context.Background()).Up())
+}
+
+func TestRegistry_Drain(t *testing.T) {
+	called := false
+	registry := NewRegistry(time.Second)
+	registry.Register("a", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	registry.Drain()
+	report := registry.Run(context.Background())
+
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusDown, report.Checks["shutdown"].Status)
+	assert.False(t, called)
+}
+
+func TestMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+
+	check := Migrations(gormDB, []interface{}{&widget{}})
+	query := `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA\(\) AND table_name IN \(\$1\)`
+	columns := []string{"table_name", "column_name"}
+
+	mock.ExpectQuery(query).WithArgs("widgets").WillReturnRows(sqlmock.NewRows(columns))
+	err = check(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "pending migration, table widgets does not exist", err.Error())
+
+	mock.ExpectQuery(query).WithArgs("widgets").WillReturnRows(sqlmock.NewRows(columns).AddRow("widgets", "id"))
+	err = check(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "pending migration, column widgets.name does not exist", err.Error())
+
+	mock.ExpectQuery(query).WithArgs("widgets").WillReturnRows(sqlmock.NewRows(columns).AddRow("widgets", "id").AddRow("widgets", "name"))
+	require.NoError(t, check(context.Background()))
+
+	// Once passed, the check no longer queries the database
+	require.NoError(t, check(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// startKey is the statement instance key holding the time the statement started
+const startKey = "metrics:start"
+
+// RegisterCallbacks times every GORM statement of the database into DBQueryDuration and counts the
+// statements slower than slowThreshold into DBSlowQueries. A zero slowThreshold counts none.
+func RegisterCallbacks(db *gorm.DB, slowThreshold time.Duration) error {
+	observe := func(operation string) func(*gorm.DB) {
+		return observer(operation, slowThreshold)
+	}
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("metrics:before_create", start),
+		callbacks.Create().After("*").Register("metrics:after_create", observe("create")),
+		callbacks.Query().Before("*").Register("metrics:before_query", start),
+		callbacks.Query().After("*").Register("metrics:after_query", observe("query")),
+		callbacks.Update().Before("*").Register("metrics:before_update", start),
+		callbacks.Update().After("*").Register("metrics:after_update", observe("update")),
+		callbacks.Delete().Before("*").Register("metrics:before_delete", start),
+		callbacks.Delete().After("*").Register("metrics:after_delete", observe("delete")),
+		callbacks.Row().Before("*").Register("metrics:before_row", start),
+		callbacks.Row().After("*").Register("metrics:after_row", observe("row")),
+		callbacks.Raw().Before("*").Register("metrics:before_raw", start),
+		callbacks.Raw().After("*").Register("metrics:after_raw", observe("raw")),
+	)
+}
+
+// RegisterDBStats exposes the sql.DBStats connection pool gauges of the database
+func RegisterDBStats(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+func start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+// observer records the duration of the statement, labelled with its table or "unknown" for raw SQL
+func observer(operation string, slowThreshold time.Duration) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		started, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		elapsed := time.Since(started.(time.Time))
+		DBQueryDuration.WithLabelValues(table, operation).Observe(elapsed.Seconds())
+		if slowThreshold > 0 && elapsed > slowThreshold {
+			DBSlowQueries.WithLabelValues(table, operation).Inc()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+type gadget struct {
+	ID uint
+}
+
+func TestRegisterCallbacks_ObservesStatements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterCallbacks(gormDB, time.Second))
+
+	mock.ExpectQuery(`SELECT \* FROM "widgets"`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+	var widgets []widget
+	require.NoError(t, gormDB.Find(&widgets).Error)
+	var one int
+	require.NoError(t, gormDB.Raw("SELECT 1").Scan(&one).Error)
+
+	assert.Equal(t, uint64(1), sampleCount(t, "widgets", "query"))
+	assert.Equal(t, uint64(1), sampleCount(t, "unknown", "row"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterCallbacks_CountsSlowStatements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterCallbacks(gormDB, 20*time.Millisecond))
+
+	mock.ExpectQuery(`SELECT \* FROM "gadgets"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "gadgets"`).WillDelayFor(50 * time.Millisecond).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var gadgets []gadget
+	require.NoError(t, gormDB.Find(&gadgets).Error)
+	assert.Equal(t, float64(0), testutil.ToFloat64(DBSlowQueries.WithLabelValues("gadgets", "query")))
+
+	require.NoError(t, gormDB.Find(&gadgets).Error)
+	assert.Equal(t, float64(1), testutil.ToFloat64(DBSlowQueries.WithLabelValues("gadgets", "query")))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// sampleCount returns how many statements DBQueryDuration observed for the table and operation
+func sampleCount(t *testing.T, table string, operation string) uint64 {
+	families, err := Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != namespace+"_db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["table"] == table && labels["operation"] == operation {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+// Package metrics holds the Prometheus collectors of the API and serves them in the text format
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "go_modules_api"
+
+// Registry holds every collector exposed by Handler
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts the handled requests by method, route template and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Handled HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the request latency by method, route template and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestsInFlight counts the requests being handled
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests being handled.",
+	})
+
+	// HTTPPanics counts the panics recovered while handling requests by method and route template
+	HTTPPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_panics_total",
+		Help:      "Panics recovered while handling HTTP requests by method and route template.",
+	}, []string{"method", "route"})
+
+	// DBQueryDuration observes the GORM statement latency by table and operation
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database statement latency by table and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+
+	// DBSlowQueries counts the GORM statements slower than the slow query threshold by table and operation
+	DBSlowQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_slow_queries_total",
+		Help:      "Database statements slower than the slow query threshold by table and operation.",
+	}, []string{"table", "operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		HTTPPanics,
+		DBQueryDuration,
+		DBSlowQueries,
+	)
+}
+
+// Handler serves the collectors of Registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey authenticates an integration as the HubClient owning it.
+// Only a SHA-256 hash of the key is stored, the prefix identifies it in listings and logs.
+type APIKey struct {
+	BaseID
+	HubClientID uint       `gorm:"index;not null" json:"hub_client_id"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name"`
+	Prefix      string     `gorm:"type:varchar(32);uniqueIndex;not null" json:"prefix"`
+	KeyHash     string     `gorm:"type:char(64);not null" json:"-"`
+	Scopes      []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RevokedAt   *time.Time `gorm:"index" json:"revoked_at"`
+	BaseTimestamps
+}
+
+// Usable reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key grants the "<resource>:<action>" scope
+func (k *APIKey) HasScope(scope string) bool {
+	return ScopesGrant(k.Scopes, scope)
+}
+
+// ScopesGrant reports whether the granted scopes include the "<resource>:<action>" scope.
+// "*" grants everything, "<resource>:*" every action and "<resource>:write" also grants read.
+func ScopesGrant(scopes []string, scope string) bool {
+	resource, action, _ := strings.Cut(scope, ":")
+	for _, granted := range scopes {
+		grantedResource, grantedAction, _ := strings.Cut(granted, ":")
+		if granted == "*" || granted == scope {
+			return true
+		}
+		if grantedResource == resource && (grantedAction == "*" || (grantedAction == "write" && action == "read")) {
+			return true
+		}
+	}
+	return false
+}
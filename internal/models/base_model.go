@@ -25,3 +25,12 @@ func (b BaseID) GetID() uint {
 type IDGetter interface {
 	GetID() uint
 }
+
+func (b BaseTimestamps) GetUpdatedAt() time.Time {
+	return b.UpdatedAt
+}
+
+// UpdatedAtGetter is an interface for models that track their last update.
+type UpdatedAtGetter interface {
+	GetUpdatedAt() time.Time
+}
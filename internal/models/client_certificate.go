@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ClientCertificate authenticates mutual TLS connections as the HubClient owning it.
+// A certificate matches by its SHA-256 Fingerprint, or by its Subject distinguished name
+// when the certificate was verified against the configured client CA bundle.
+type ClientCertificate struct {
+	BaseID
+	HubClientID uint       `gorm:"index;not null" json:"hub_client_id"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name"`
+	Fingerprint string     `gorm:"type:varchar(64);index" json:"fingerprint,omitempty"`
+	Subject     string     `gorm:"type:varchar(255);index" json:"subject,omitempty"`
+	Scopes      []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RevokedAt   *time.Time `gorm:"index" json:"revoked_at"`
+	BaseTimestamps
+}
+
+// Usable reports whether the certificate mapping is neither revoked nor expired at the given time
+func (c *ClientCertificate) Usable(now time.Time) bool {
+	return c.RevokedAt == nil && (c.ExpiresAt == nil || now.Before(*c.ExpiresAt))
+}
+
+// HasScope reports whether the certificate grants the "<resource>:<action>" scope
+func (c *ClientCertificate) HasScope(scope string) bool {
+	return ScopesGrant(c.Scopes, scope)
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the first response of a request sent with an Idempotency-Key header
+// so retries can be replayed instead of executed again.
+// Keys are unique per caller: the principal and hub client that sent the request. The hub client
+// column isn't named HubClientID so the tenant callbacks leave the table alone, keys are scoped
+// by the repository instead.
+type IdempotencyKey struct {
+	BaseID
+	Principal         string            `gorm:"type:varchar(100);uniqueIndex:idx_idempotency_keys_owner_key;not null"`
+	CallerHubClientID uint              `gorm:"column:hub_client_id;uniqueIndex:idx_idempotency_keys_owner_key;not null;default:0"`
+	Key               string            `gorm:"type:varchar(255);uniqueIndex:idx_idempotency_keys_owner_key;not null"`
+	Method            string            `gorm:"type:varchar(10);not null"`
+	Path              string            `gorm:"type:text;not null"`
+	RequestHash       string            `gorm:"type:char(64);not null"`
+	Completed         bool              `gorm:"default:false"`
+	StatusCode        int               `gorm:"default:0"`
+	ContentType       string            `gorm:"type:varchar(255)"`
+	ResponseHeaders   map[string]string `gorm:"type:jsonb;serializer:json"`
+	ResponseBody      []byte            `gorm:"type:bytea"`
+	ExpiresAt         time.Time         `gorm:"index;not null"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+// Migrated lists every model managed by the migrate command, in creation order
+func Migrated() []interface{} {
+	return []interface{}{
+		&HubClient{},
+		&Role{},
+		&User{},
+		&PasswordResetToken{},
+		&TokenRevocation{},
+		&APIKey{},
+		&OAuthClientSecret{},
+		&OAuthAccessToken{},
+		&ClientCertificate{},
+		&Module{},
+		&ModulePermission{},
+		&EntityRegister{},
+		&ModuleEntityRegister{},
+		&Product{},
+		&EntityRegisterProduct{},
+		&MenuItem{},
+		&MenuItemPermission{},
+		&IdempotencyKey{},
+		&SchemaMigration{},
+	}
+}
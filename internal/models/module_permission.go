@@ -2,15 +2,24 @@ package models
 
 import "time"
 
+// Actions a ModulePermission can grant on a module
+const (
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionAll grants every action on the module
+	ActionAll = "*"
+)
+
 type ModulePermission struct {
-	ID        uint `gorm:"primaryKey"`
-	ModuleID  uint `gorm:"not null;index"`
-	RoleID    uint `gorm:"not null;index"`
+	ID        uint   `gorm:"primaryKey"`
+	ModuleID  uint   `gorm:"not null;index;uniqueIndex:idx_module_role_action"`
+	RoleID    uint   `gorm:"not null;index;uniqueIndex:idx_module_role_action"`
+	Action    string `gorm:"size:20;not null;default:'*';uniqueIndex:idx_module_role_action"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
 	Module Module `gorm:"foreignKey:ModuleID"`
 	Role   Role   `gorm:"foreignKey:RoleID"`
-
-	_ struct{} `gorm:"uniqueIndex:idx_module_role"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// OAuthAccessToken is an opaque bearer token issued by the client credentials grant.
+// Only a SHA-256 hash of the token is stored, so it can be introspected and revoked.
+type OAuthAccessToken struct {
+	BaseID
+	HubClientID    uint      `gorm:"index;not null"`
+	ClientSecretID uint      `gorm:"index;not null"`
+	TokenHash      string    `gorm:"type:char(64);uniqueIndex;not null"`
+	Scopes         []string  `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt      time.Time `gorm:"index;not null"`
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+// Active reports whether the token is neither revoked nor expired at the given time
+func (t *OAuthAccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// HasScope reports whether the token grants the "<resource>:<action>" scope
+func (t *OAuthAccessToken) HasScope(scope string) bool {
+	return ScopesGrant(t.Scopes, scope)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// OAuthClientSecret authenticates a HubClient to the OAuth2 token endpoint, where the hub client
+// ExternalID is its client_id. Only a SHA-256 hash of the secret is stored.
+// The scopes bound the access tokens issued with the secret.
+type OAuthClientSecret struct {
+	BaseID
+	HubClientID uint       `gorm:"index;not null" json:"hub_client_id"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name"`
+	Prefix      string     `gorm:"type:varchar(32);uniqueIndex;not null" json:"prefix"`
+	SecretHash  string     `gorm:"type:char(64);not null" json:"-"`
+	Scopes      []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RevokedAt   *time.Time `gorm:"index" json:"revoked_at"`
+	BaseTimestamps
+}
+
+// Usable reports whether the secret is neither revoked nor expired at the given time
+func (s *OAuthClientSecret) Usable(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PasswordResetToken lets a user who forgot their password set a new one.
+// Only a SHA-256 hash of the token is stored and a token can be used once.
+type PasswordResetToken struct {
+	BaseID
+	UserID    uint      `gorm:"index;not null"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Usable reports whether the token is neither used nor expired at the given time
+func (t *PasswordResetToken) Usable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
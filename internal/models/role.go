@@ -1,9 +1,13 @@
 package models
 
+// Role groups the ModulePermission grants of users.
+// Super roles are granted every action. The flag can't be set through the API, the migrate
+// command sets it on the role named by RBAC_SUPER_ROLE.
 type Role struct {
 	BaseID
-	Name string `gorm:"type:varchar(50);not null" json:"name"`
-	Slug string `gorm:"type:varchar(50);not null" json:"slug"`
+	Name  string `gorm:"type:varchar(50);not null" json:"name"`
+	Slug  string `gorm:"type:varchar(50);uniqueIndex;not null" json:"slug"`
+	Super bool   `gorm:"default:false;not null" json:"super"`
 	BaseAttributes
 	BaseTimestamps
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SchemaMigration records a run of the migrate command and the build that applied it.
+// The latest record is the migration version of the database.
+type SchemaMigration struct {
+	BaseID
+	Version   string    `gorm:"type:varchar(255);not null" json:"version"`
+	Commit    string    `gorm:"type:varchar(255)" json:"commit"`
+	AppliedAt time.Time `gorm:"index;not null" json:"applied_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Kinds of TokenRevocation
+const (
+	// RevokedToken revokes the single token whose jti is the Key
+	RevokedToken = "token"
+	// RevokedUser revokes the tokens issued to the user whose ID is the Key up to RevokedAt
+	RevokedUser = "user"
+	// RevokedHubClient revokes the tokens issued for the hub client whose ID is the Key up to RevokedAt
+	RevokedHubClient = "hub_client"
+)
+
+// TokenRevocation rejects user tokens before they expire. A revocation is only kept until
+// every token it matches has expired.
+type TokenRevocation struct {
+	BaseID
+	Kind      string    `gorm:"type:varchar(20);uniqueIndex:idx_token_revocations_kind_key;not null"`
+	Key       string    `gorm:"type:varchar(255);uniqueIndex:idx_token_revocations_kind_key;not null"`
+	RevokedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
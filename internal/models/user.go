@@ -0,0 +1,15 @@
+package models
+
+// User is a person signing in to the API on behalf of the HubClient owning it.
+// Users are tenant-owned, their access is granted by the assigned roles.
+type User struct {
+	BaseID
+	HubClientID uint       `gorm:"index;not null" json:"hub_client_id"`
+	HubClient   *HubClient `gorm:"foreignKey:HubClientID" json:"hub_client,omitempty"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name"`
+	Email       string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password    string     `gorm:"type:varchar(255);not null" json:"-"`
+	Roles       []Role     `gorm:"many2many:user_roles" json:"roles,omitempty"`
+	BaseAttributes
+	BaseTimestamps
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket refilled lazily on every take
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket is full again, after which it can be dropped
+	full time.Time
+}
+
+// MemoryStore keeps the buckets of a single instance in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// sweepInterval is how often full buckets are dropped to bound memory
+const sweepInterval = time.Minute
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take removes a token from every bucket when all of them have one
+func (s *MemoryStore) Take(buckets []Bucket, now time.Time) ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	refilled := make([]*bucket, len(buckets))
+	allowed := true
+	for i, requested := range buckets {
+		refilled[i] = s.refill(requested.Key, requested.Rate, now)
+		allowed = allowed && refilled[i].tokens >= 1
+	}
+
+	results := make([]Result, len(buckets))
+	for i, requested := range buckets {
+		b := refilled[i]
+		capacity := float64(requested.Rate.Limit)
+		perToken := requested.Rate.Period / time.Duration(requested.Rate.Limit)
+
+		result := Result{Allowed: allowed}
+		if allowed {
+			b.tokens--
+		} else if b.tokens < 1 {
+			result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+		}
+
+		result.Remaining = int(b.tokens)
+		result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+		b.full = now.Add(result.Reset)
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+// Refund returns a token to every bucket, without exceeding its capacity
+func (s *MemoryStore) Refund(buckets []Bucket, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, requested := range buckets {
+		b := s.refill(requested.Key, requested.Rate, now)
+		capacity := float64(requested.Rate.Limit)
+		perToken := requested.Rate.Period / time.Duration(requested.Rate.Limit)
+
+		b.tokens = math.Min(capacity, b.tokens+1)
+		b.full = now.Add(time.Duration((capacity - b.tokens) * float64(perToken)))
+	}
+	return nil
+}
+
+// refill returns the bucket of key with the tokens earned since its last update, a new bucket starts full
+func (s *MemoryStore) refill(key string, rate Rate, now time.Time) *bucket {
+	capacity := float64(rate.Limit)
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now, full: now}
+		s.buckets[key] = b
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+	return b
+}
+
+// sweep drops the buckets that are full again, since a new bucket starts full
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Ensure MemoryStore implements Store.
+var _ Store = &MemoryStore{}
@@ -0,0 +1,104 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/ratelimit"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	rate := ratelimit.Rate{Limit: 2, Period: 2 * time.Second}
+	now := time.Now()
+	take := func(key string, at time.Time) ratelimit.Result {
+		results, err := store.Take([]ratelimit.Bucket{{Key: key, Rate: rate}}, at)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	result := take("ip:1", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, time.Second, result.Reset)
+
+	result = take("ip:1", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = take("ip:1", now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	// Other keys have their own bucket
+	result = take("ip:2", now)
+	assert.True(t, result.Allowed)
+
+	// One token is refilled every second
+	result = take("ip:1", now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestMemoryStore_TakeAllOrNothing(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+	strict := ratelimit.Bucket{Key: "api_key:1", Rate: ratelimit.Rate{Limit: 1, Period: time.Minute}}
+	loose := ratelimit.Bucket{Key: "ip:1", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}}
+
+	results, err := store.Take([]ratelimit.Bucket{strict, loose}, now)
+	require.NoError(t, err)
+	assert.True(t, results[0].Allowed)
+	assert.Equal(t, 9, results[1].Remaining)
+
+	// The empty bucket denies the request without draining the other one
+	for i := 0; i < 5; i++ {
+		results, err = store.Take([]ratelimit.Bucket{strict, loose}, now)
+		require.NoError(t, err)
+		assert.False(t, results[0].Allowed)
+		assert.False(t, results[1].Allowed)
+		assert.Equal(t, time.Minute, results[0].RetryAfter)
+		assert.Zero(t, results[1].RetryAfter)
+		assert.Equal(t, 9, results[1].Remaining)
+	}
+}
+
+func TestMemoryStore_Refund(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+	ip := ratelimit.Bucket{Key: "ip:1", Rate: ratelimit.Rate{Limit: 2, Period: time.Minute}}
+
+	results, err := store.Take([]ratelimit.Bucket{ip}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, results[0].Remaining)
+
+	require.NoError(t, store.Refund([]ratelimit.Bucket{ip}, now))
+	require.NoError(t, store.Refund([]ratelimit.Bucket{ip}, now))
+
+	// Refunds never exceed the capacity of the bucket
+	results, err = store.Take([]ratelimit.Bucket{ip}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, results[0].Remaining)
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ratelimit.ParsePolicies(map[string]string{
+		"default.ip":      "300/1m",
+		"default.api_key": "600/1m",
+		"auth.ip":         "10/1m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Rate{Limit: 300, Period: time.Minute}, policies["default"][ratelimit.PrincipalIP])
+	assert.Equal(t, ratelimit.Rate{Limit: 10, Period: time.Minute}, policies["auth"][ratelimit.PrincipalIP])
+
+	_, err = ratelimit.ParsePolicies(map[string]string{"default.user": "10/1m"})
+	assert.Error(t, err)
+
+	_, err = ratelimit.ParsePolicies(map[string]string{"default.ip": "10"})
+	assert.Error(t, err)
+}
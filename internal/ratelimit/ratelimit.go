@@ -0,0 +1,108 @@
+// Package ratelimit implements token bucket rate limiting behind a pluggable Store.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Principals a limit can apply to
+const (
+	PrincipalAPIKey    = "api_key"
+	PrincipalHubClient = "hub_client"
+	PrincipalIP        = "ip"
+	// PrincipalClientID is the OAuth2 client_id a request authenticates with
+	PrincipalClientID = "client_id"
+)
+
+// Rate allows bursts of Limit requests, refilled evenly over Period
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// String formats the rate as "<limit>/<period>"
+func (r Rate) String() string {
+	return strconv.Itoa(r.Limit) + "/" + r.Period.String()
+}
+
+// Result is the state of a bucket after a Take
+type Result struct {
+	// Allowed reports whether a token was taken
+	Allowed bool
+	// Remaining is the number of whole tokens left
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero when Allowed
+	RetryAfter time.Duration
+}
+
+// Bucket names the bucket of a principal and its rate
+type Bucket struct {
+	Key  string
+	Rate Rate
+}
+
+// Store keeps the buckets. The in-memory store is used by default,
+// a shared store lets several instances enforce the same limits.
+type Store interface {
+	// Take removes a token from every bucket when all of them have one, and none otherwise,
+	// so a request denied by one bucket doesn't drain the others. Results follow the buckets order.
+	Take(buckets []Bucket, now time.Time) ([]Result, error)
+	// Refund returns the token taken from every bucket, when a later limit of the same request denies it
+	Refund(buckets []Bucket, now time.Time) error
+}
+
+// Policies maps a route group to the rate of each principal
+type Policies map[string]map[string]Rate
+
+// ParseRate parses "<limit>/<period>", where the period is a Go duration such as 1m or 1h
+func ParseRate(value string) (Rate, error) {
+	limit, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Rate{}, fmt.Errorf("invalid rate %q, expected <limit>/<period>", value)
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q, the limit must be a positive integer", value)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q, the period must be a positive duration", value)
+	}
+
+	return Rate{Limit: n, Period: d}, nil
+}
+
+// ParsePolicies parses "<group>.<principal>" keys holding "<limit>/<period>" rates
+func ParsePolicies(values map[string]string) (Policies, error) {
+	policies := Policies{}
+	for key, value := range values {
+		group, principal, found := strings.Cut(strings.TrimSpace(key), ".")
+		if !found || group == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected <group>.<principal>", key)
+		}
+
+		switch principal {
+		case PrincipalAPIKey, PrincipalHubClient, PrincipalIP, PrincipalClientID:
+		default:
+			return nil, fmt.Errorf("invalid rate limit %q, the principal must be %s, %s, %s or %s", key, PrincipalAPIKey, PrincipalHubClient, PrincipalIP, PrincipalClientID)
+		}
+
+		rate, err := ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit %q: %w", key, err)
+		}
+
+		if policies[group] == nil {
+			policies[group] = map[string]Rate{}
+		}
+		policies[group][principal] = rate
+	}
+	return policies, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository defines the interface for database operations related to API keys.
+// API keys are tenant-owned, so the context must carry their hub client or be tenant.WithoutScope.
+type APIKeyRepository interface {
+	ListByHubClient(ctx context.Context, hubClientID uint) ([]models.APIKey, error)
+	GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	Revoke(ctx context.Context, key *models.APIKey, at time.Time) error
+	Rotate(ctx context.Context, old *models.APIKey, replacement *models.APIKey, at time.Time) error
+	TouchLastUsed(ctx context.Context, key *models.APIKey, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository.
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// ListByHubClient returns every key of the hub client, newest first.
+func (r *apiKeyRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// GetByHubClient returns a key only when it belongs to the hub client.
+func (r *apiKeyRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByPrefix returns the key identified by its public prefix.
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create inserts a new key.
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// Revoke marks the key as revoked at the given time.
+func (r *apiKeyRepository) Revoke(ctx context.Context, key *models.APIKey, at time.Time) error {
+	key.RevokedAt = &at
+	return r.db.WithContext(ctx).Model(key).Update("revoked_at", at).Error
+}
+
+// Rotate revokes the old key and inserts its replacement in a single transaction.
+func (r *apiKeyRepository) Rotate(ctx context.Context, old *models.APIKey, replacement *models.APIKey, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(old).Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(replacement).Error; err != nil {
+			return err
+		}
+		old.RevokedAt = &at
+		return nil
+	})
+}
+
+// TouchLastUsed records when the key was last used without bumping updated_at.
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, key *models.APIKey, at time.Time) error {
+	key.LastUsedAt = &at
+	return r.db.WithContext(ctx).Model(key).UpdateColumn("last_used_at", at).Error
+}
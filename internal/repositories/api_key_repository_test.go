@@ -0,0 +1,91 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newTenantDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, tenant.RegisterCallbacks(gormDB))
+
+	return gormDB, mock
+}
+
+func TestAPIKeyRepository_TenantScope(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewAPIKeyRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE prefix = \$1 AND "api_keys"\."hub_client_id" = \$2 ORDER BY "api_keys"\."id" LIMIT \$3`).
+		WithArgs("gma_0123456789ab", 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hub_client_id", "prefix"}).AddRow(1, 4, "gma_0123456789ab"))
+
+	key, err := repo.GetByPrefix(tenant.WithHubClientID(context.Background(), 4), "gma_0123456789ab")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), key.HubClientID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_TenantRequired(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewAPIKeyRepository(gormDB)
+
+	_, err := repo.GetByPrefix(context.Background(), "gma_0123456789ab")
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.ListByHubClient(context.Background(), 4)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_WithoutScope(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewAPIKeyRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE prefix = \$1 ORDER BY "api_keys"\."id" LIMIT \$2`).
+		WithArgs("gma_0123456789ab", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hub_client_id", "prefix"}).AddRow(1, 4, "gma_0123456789ab"))
+
+	_, err := repo.GetByPrefix(tenant.WithoutScope(context.Background()), "gma_0123456789ab")
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_CreateAssignsTenant(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewAPIKeyRepository(gormDB)
+	ctx := tenant.WithHubClientID(context.Background(), 4)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "api_keys"`).
+		WithArgs(4, "Integration", "gma_0123456789ab", "hash", `["roles:read"]`, nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	key := &models.APIKey{Name: "Integration", Prefix: "gma_0123456789ab", KeyHash: "hash", Scopes: []string{"roles:read"}}
+	assert.NoError(t, repo.Create(ctx, key))
+	assert.Equal(t, uint(4), key.HubClientID)
+
+	// Records of another tenant are rejected before reaching the database
+	other := &models.APIKey{HubClientID: 5, Name: "Other", Prefix: "gma_ba9876543210", KeyHash: "hash", Scopes: []string{"*"}}
+	err := repo.Create(ctx, other)
+	assert.ErrorIs(t, err, tenant.ErrCrossTenant)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
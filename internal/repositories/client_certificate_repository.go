@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ClientCertificateRepository defines the interface for database operations related to client certificates.
+// Client certificates are tenant-owned, so the context must carry their hub client or be tenant.WithoutScope.
+type ClientCertificateRepository interface {
+	ListByHubClient(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error)
+	GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.ClientCertificate, error)
+	GetByFingerprint(ctx context.Context, fingerprint string) (*models.ClientCertificate, error)
+	GetBySubject(ctx context.Context, subject string) (*models.ClientCertificate, error)
+	Create(ctx context.Context, certificate *models.ClientCertificate) error
+	Revoke(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error
+	TouchLastUsed(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error
+}
+
+type clientCertificateRepository struct {
+	db *gorm.DB
+}
+
+// NewClientCertificateRepository creates a new instance of ClientCertificateRepository.
+func NewClientCertificateRepository(db *gorm.DB) ClientCertificateRepository {
+	return &clientCertificateRepository{db: db}
+}
+
+// ListByHubClient returns every certificate of the hub client, newest first.
+func (r *clientCertificateRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error) {
+	var certificates []models.ClientCertificate
+	err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).Order("id DESC").Find(&certificates).Error
+	return certificates, err
+}
+
+// GetByHubClient returns a certificate only when it belongs to the hub client.
+func (r *clientCertificateRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.ClientCertificate, error) {
+	var certificate models.ClientCertificate
+	if err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).First(&certificate, id).Error; err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// GetByFingerprint returns the newest unrevoked certificate with the fingerprint.
+func (r *clientCertificateRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*models.ClientCertificate, error) {
+	return r.getUnrevoked(ctx, "fingerprint = ?", fingerprint)
+}
+
+// GetBySubject returns the newest unrevoked certificate with the subject.
+func (r *clientCertificateRepository) GetBySubject(ctx context.Context, subject string) (*models.ClientCertificate, error) {
+	return r.getUnrevoked(ctx, "subject = ?", subject)
+}
+
+// Create inserts a new certificate.
+func (r *clientCertificateRepository) Create(ctx context.Context, certificate *models.ClientCertificate) error {
+	return r.db.WithContext(ctx).Create(certificate).Error
+}
+
+// Revoke marks the certificate as revoked at the given time.
+func (r *clientCertificateRepository) Revoke(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error {
+	certificate.RevokedAt = &at
+	return r.db.WithContext(ctx).Model(certificate).Update("revoked_at", at).Error
+}
+
+// TouchLastUsed records when the certificate was last used without bumping updated_at.
+func (r *clientCertificateRepository) TouchLastUsed(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error {
+	certificate.LastUsedAt = &at
+	return r.db.WithContext(ctx).Model(certificate).UpdateColumn("last_used_at", at).Error
+}
+
+func (r *clientCertificateRepository) getUnrevoked(ctx context.Context, query string, value string) (*models.ClientCertificate, error) {
+	var certificate models.ClientCertificate
+	err := r.db.WithContext(ctx).Where(query, value).Where("revoked_at IS NULL").Order("id DESC").First(&certificate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
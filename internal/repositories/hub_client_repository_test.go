@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -301,3 +302,26 @@ func TestHubClientRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestHubClientRepository_ScopedTo(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewHubClientRepository(gormDB).ScopedTo(4).WithContext(context.Background())
+
+	mock.ExpectQuery(`SELECT \* FROM "hub_clients" WHERE hub_clients\.id = \$1 AND "hub_clients"\."id" = \$2 AND is_deleted = \$3 ORDER BY "hub_clients"\."id" LIMIT \$4`).
+		WithArgs(4, 5, false, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByID(5)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// The scope applies to every statement, not only the first one
+	mock.ExpectQuery(`SELECT count\(\*\) AS count, max\(updated_at\) AS last_modified FROM "hub_clients" WHERE hub_clients\.id = \$1 AND is_deleted = \$2`).
+		WithArgs(4, false).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "last_modified"}).AddRow(1, nil))
+
+	count, _, err := repo.LastModified("", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
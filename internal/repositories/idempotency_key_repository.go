@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository defines the interface for database operations related to idempotency keys.
+type IdempotencyKeyRepository interface {
+	Reserve(record *models.IdempotencyKey) (bool, error)
+	GetByKey(principal string, hubClientID uint, key string) (*models.IdempotencyKey, error)
+	Update(record *models.IdempotencyKey) error
+	Delete(record *models.IdempotencyKey) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new instance of IdempotencyKeyRepository.
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Reserve inserts the record unless its caller already used its key, reporting whether it was inserted.
+func (r *idempotencyKeyRepository) Reserve(record *models.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "principal"}, {Name: "hub_client_id"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(record)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetByKey returns the record the principal of the hub client stored for the given key.
+func (r *idempotencyKeyRepository) GetByKey(principal string, hubClientID uint, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	if err := r.db.Where("principal = ? AND hub_client_id = ? AND key = ?", principal, hubClientID, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Update saves every field of the record.
+func (r *idempotencyKeyRepository) Update(record *models.IdempotencyKey) error {
+	return r.db.Save(record).Error
+}
+
+// Delete removes the record from the database.
+func (r *idempotencyKeyRepository) Delete(record *models.IdempotencyKey) error {
+	return r.db.Delete(record).Error
+}
+
+// DeleteExpired removes every record expired at the given time and returns how many were deleted.
+func (r *idempotencyKeyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ModulePermissionRepository defines the interface for database operations related to module permissions.
+type ModulePermissionRepository interface {
+	HasGrant(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error)
+}
+
+type modulePermissionRepository struct {
+	db *gorm.DB
+}
+
+// NewModulePermissionRepository creates a new instance of ModulePermissionRepository.
+func NewModulePermissionRepository(db *gorm.DB) ModulePermissionRepository {
+	return &modulePermissionRepository{db: db}
+}
+
+// HasGrant reports whether any of the roles is granted the action, or every action, on an active
+// module of the given type. A nil hubClientID matches the modules of every hub client.
+func (r *modulePermissionRepository) HasGrant(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error) {
+	if len(roleIDs) == 0 {
+		return false, nil
+	}
+
+	query := r.db.Model(&models.ModulePermission{}).
+		Joins("JOIN modules ON modules.id = module_permissions.module_id").
+		Where("module_permissions.role_id IN ?", roleIDs).
+		Where("module_permissions.action IN ?", []string{action, models.ActionAll}).
+		Where("modules.type = ? AND modules.active = ? AND modules.is_deleted = ?", module, true, false)
+	if hubClientID != nil {
+		query = query.Where("modules.hub_client_id = ?", *hubClientID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,38 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-modules-api/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestModulePermissionRepository_HasGrant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := repositories.NewModulePermissionRepository(gormDB)
+	hubClientID := uint(3)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "module_permissions" JOIN modules ON modules\.id = module_permissions\.module_id WHERE module_permissions\.role_id IN \(\$1,\$2\) AND module_permissions\.action IN \(\$3,\$4\) AND \(modules\.type = \$5 AND modules\.active = \$6 AND modules\.is_deleted = \$7\) AND modules\.hub_client_id = \$8`).
+		WithArgs(1, 2, "update", "*", "roles", true, false, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	allowed, err := repo.HasGrant([]uint{1, 2}, "roles", "update", &hubClientID)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Callers without roles are denied without a query
+	allowed, err = repo.HasGrant(nil, "roles", "update", nil)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// OAuthAccessTokenRepository defines the interface for database operations related to OAuth2 access tokens.
+// Access tokens are tenant-owned, so the context must carry their hub client or be tenant.WithoutScope.
+type OAuthAccessTokenRepository interface {
+	Create(ctx context.Context, token *models.OAuthAccessToken) error
+	GetByHash(ctx context.Context, hash string) (*models.OAuthAccessToken, error)
+	Revoke(ctx context.Context, token *models.OAuthAccessToken, at time.Time) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type oauthAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthAccessTokenRepository creates a new instance of OAuthAccessTokenRepository.
+func NewOAuthAccessTokenRepository(db *gorm.DB) OAuthAccessTokenRepository {
+	return &oauthAccessTokenRepository{db: db}
+}
+
+// Create inserts a new token.
+func (r *oauthAccessTokenRepository) Create(ctx context.Context, token *models.OAuthAccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByHash returns the token with the given hash.
+func (r *oauthAccessTokenRepository) GetByHash(ctx context.Context, hash string) (*models.OAuthAccessToken, error) {
+	var token models.OAuthAccessToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke marks the token as revoked at the given time.
+func (r *oauthAccessTokenRepository) Revoke(ctx context.Context, token *models.OAuthAccessToken, at time.Time) error {
+	token.RevokedAt = &at
+	return r.db.WithContext(ctx).Model(token).Update("revoked_at", at).Error
+}
+
+// DeleteExpired removes the tokens expired before the given time and returns how many were deleted.
+func (r *oauthAccessTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&models.OAuthAccessToken{})
+	return result.RowsAffected, result.Error
+}
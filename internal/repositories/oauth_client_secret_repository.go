@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// OAuthClientSecretRepository defines the interface for database operations related to OAuth2 client secrets.
+// Client secrets are tenant-owned, so the context must carry their hub client or be tenant.WithoutScope.
+type OAuthClientSecretRepository interface {
+	ListByHubClient(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error)
+	GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.OAuthClientSecret, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.OAuthClientSecret, error)
+	Create(ctx context.Context, secret *models.OAuthClientSecret) error
+	Revoke(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error
+	TouchLastUsed(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error
+}
+
+type oauthClientSecretRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientSecretRepository creates a new instance of OAuthClientSecretRepository.
+func NewOAuthClientSecretRepository(db *gorm.DB) OAuthClientSecretRepository {
+	return &oauthClientSecretRepository{db: db}
+}
+
+// ListByHubClient returns every secret of the hub client, newest first.
+func (r *oauthClientSecretRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error) {
+	var secrets []models.OAuthClientSecret
+	err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).Order("id DESC").Find(&secrets).Error
+	return secrets, err
+}
+
+// GetByHubClient returns a secret only when it belongs to the hub client.
+func (r *oauthClientSecretRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.OAuthClientSecret, error) {
+	var secret models.OAuthClientSecret
+	if err := r.db.WithContext(ctx).Where("hub_client_id = ?", hubClientID).First(&secret, id).Error; err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// GetByPrefix returns the secret identified by its public prefix.
+func (r *oauthClientSecretRepository) GetByPrefix(ctx context.Context, prefix string) (*models.OAuthClientSecret, error) {
+	var secret models.OAuthClientSecret
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&secret).Error; err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// Create inserts a new secret.
+func (r *oauthClientSecretRepository) Create(ctx context.Context, secret *models.OAuthClientSecret) error {
+	return r.db.WithContext(ctx).Create(secret).Error
+}
+
+// Revoke marks the secret as revoked and revokes the active tokens issued with it in a single transaction.
+func (r *oauthClientSecretRepository) Revoke(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(secret).Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.OAuthAccessToken{}).
+			Where("client_secret_id = ? AND revoked_at IS NULL AND expires_at > ?", secret.ID, at).
+			Update("revoked_at", at).Error
+		if err != nil {
+			return err
+		}
+		secret.RevokedAt = &at
+		return nil
+	})
+}
+
+// TouchLastUsed records when the secret was last used without bumping updated_at.
+func (r *oauthClientSecretRepository) TouchLastUsed(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error {
+	secret.LastUsedAt = &at
+	return r.db.WithContext(ctx).Model(secret).UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// PasswordResetTokenRepository defines the interface for database operations related to password reset tokens.
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	Consume(ctx context.Context, token *models.PasswordResetToken, passwordHash string, at time.Time) error
+}
+
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository creates a new instance of PasswordResetTokenRepository.
+func NewPasswordResetTokenRepository(db *gorm.DB) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{db: db}
+}
+
+// Create inserts a new token.
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByHash returns the token with the given hash.
+func (r *passwordResetTokenRepository) GetByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume sets the new password of the token user, marks the token used and deletes
+// every other token of the user in a single transaction.
+// The context must be allowed to update the user, e.g. tenant.WithoutScope.
+func (r *passwordResetTokenRepository) Consume(ctx context.Context, token *models.PasswordResetToken, passwordHash string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(token).Where("used_at IS NULL").UpdateColumn("used_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := updatePassword(tx, token.UserID, passwordHash); err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id <> ?", token.UserID, token.ID).Delete(&models.PasswordResetToken{}).Error
+	})
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ResourceRepositoryInterface extends BaseRepositoryInterface with the listing operations shared by every resource.
+type ResourceRepositoryInterface[T any] interface {
+	BaseRepositoryInterface[*T]
+	Pagination(search string, active *bool, sortField string, sortOrder string, page int, pageSize int) ([]T, int64, error)
+	GetAll(search string, active *bool, sortField string, sortOrder string) ([]T, error)
+	LastModified(search string, active *bool) (int64, time.Time, error)
+	WithContext(ctx context.Context) ResourceRepositoryInterface[T]
+}
+
+// ResourceRepository provides CRUD, filtering, sorting and pagination for a model type T.
+type ResourceRepository[T any, PT interface {
+	*T
+	models.IDGetter
+}] struct {
+	*BaseRepository[PT]
+	db            *gorm.DB
+	searchColumns []string
+}
+
+// NewResourceRepository creates a new ResourceRepository that matches the search term against the given columns.
+func NewResourceRepository[T any, PT interface {
+	*T
+	models.IDGetter
+}](db *gorm.DB, searchColumns ...string) *ResourceRepository[T, PT] {
+	return &ResourceRepository[T, PT]{
+		BaseRepository: NewBaseRepository[PT](db),
+		db:             db,
+		searchColumns:  searchColumns,
+	}
+}
+
+// WithContext returns a copy of the repository running its queries with ctx,
+// which carries the tenant of tenant-owned models.
+func (r *ResourceRepository[T, PT]) WithContext(ctx context.Context) ResourceRepositoryInterface[T] {
+	db := r.db.WithContext(ctx)
+	repo := &ResourceRepository[T, PT]{
+		BaseRepository: NewBaseRepository[PT](db),
+		db:             db,
+		searchColumns:  r.searchColumns,
+	}
+	// PT is always *T, which the compiler cannot prove inside the generic method
+	return any(repo).(ResourceRepositoryInterface[T])
+}
+
+// filter applies the search and active filters to the query.
+func (r *ResourceRepository[T, PT]) filter(query *gorm.DB, search string, active *bool) *gorm.DB {
+	if search != "" && len(r.searchColumns) > 0 {
+		conditions := make([]string, len(r.searchColumns))
+		args := make([]interface{}, len(r.searchColumns))
+		for i, column := range r.searchColumns {
+			conditions[i] = column + " ILIKE ?"
+			args[i] = "%" + search + "%"
+		}
+		query = query.Where(strings.Join(conditions, " OR "), args...)
+	}
+
+	if active != nil {
+		query = query.Where("active = ?", *active)
+	}
+
+	return query
+}
+
+// applySort applies the requested ordering to the query, defaulting to ascending.
+func applySort(query *gorm.DB, sortField string, sortOrder string) *gorm.DB {
+	if sortField == "" {
+		return query
+	}
+	if sortOrder != "desc" {
+		sortOrder = "asc"
+	}
+	return query.Order(sortField + " " + sortOrder)
+}
+
+// Pagination returns a page of records matching the filters along with the total count.
+func (r *ResourceRepository[T, PT]) Pagination(search string, active *bool, sortField string, sortOrder string, page int, pageSize int) ([]T, int64, error) {
+	var entities []T
+	var total int64
+
+	query := r.filter(r.db.Model(new(T)).Scopes(scopeNotDeleted), search, active)
+
+	// Count the total records after applying filters.
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	err := applySort(query, sortField, sortOrder).Offset(offset).Limit(pageSize).Find(&entities).Error
+
+	return entities, total, err
+}
+
+// GetAll returns all records matching the filters and sorting options.
+func (r *ResourceRepository[T, PT]) GetAll(search string, active *bool, sortField string, sortOrder string) ([]T, error) {
+	var entities []T
+
+	query := r.filter(r.db.Model(new(T)).Scopes(scopeNotDeleted), search, active)
+
+	err := applySort(query, sortField, sortOrder).Find(&entities).Error
+	return entities, err
+}
+
+// LastModified returns the number of records matching the filters and their latest update time,
+// which together change whenever the filtered collection does.
+func (r *ResourceRepository[T, PT]) LastModified(search string, active *bool) (int64, time.Time, error) {
+	var result struct {
+		Count        int64
+		LastModified *time.Time
+	}
+
+	query := r.filter(r.db.Model(new(T)).Scopes(scopeNotDeleted), search, active)
+
+	err := query.Select("count(*) AS count, max(updated_at) AS last_modified").Scan(&result).Error
+	if err != nil || result.LastModified == nil {
+		return result.Count, time.Time{}, err
+	}
+	return result.Count, *result.LastModified, nil
+}
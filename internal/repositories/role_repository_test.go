@@ -2,6 +2,7 @@ package repositories_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -293,3 +294,82 @@ func TestRoleRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleRepository_LastModified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := repositories.NewRoleRepository(gormDB)
+
+	updatedAt := time.Date(2025, 2, 1, 18, 8, 26, 0, time.UTC)
+	mock.ExpectQuery(`SELECT count\(\*\) AS count, max\(updated_at\) AS last_modified FROM "roles" WHERE \(name ILIKE \$1 OR slug ILIKE \$2\) AND is_deleted = \$3`).
+		WithArgs("%adm%", "%adm%", false).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "last_modified"}).AddRow(2, updatedAt))
+
+	count, lastModified, err := repo.LastModified("adm", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.True(t, updatedAt.Equal(lastModified))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepository_GetByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := repositories.NewRoleRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT "roles"\."id",.+ FROM "roles" JOIN user_roles ON user_roles\.role_id = roles\.id WHERE \(user_roles\.user_id = \$1 AND roles\.active = \$2\) AND is_deleted = \$3`).
+		WithArgs(7, true, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug"}).AddRow(1, "Admin", "admin"))
+
+	roles, err := repo.GetByUser(7)
+	assert.NoError(t, err)
+	if assert.Len(t, roles, 1) {
+		assert.Equal(t, "admin", roles[0].Slug)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepository_EnsureSuper(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewRoleRepository(gormDB)
+
+	// An existing role is promoted
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "roles" WHERE slug = \$1 AND is_deleted = \$2 ORDER BY "roles"\."id" LIMIT \$3`).
+		WithArgs("admin", false, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "super"}).AddRow(4, "admin", false))
+	mock.ExpectExec(`UPDATE "roles" SET "super"=\$1,"updated_at"=\$2 WHERE "id" = \$3`).
+		WithArgs(true, sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	role, err := repo.EnsureSuper("admin")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), role.ID)
+	assert.True(t, role.Super)
+
+	// A missing role is created
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "roles" WHERE slug = \$1 AND is_deleted = \$2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`INSERT INTO "roles" \("name","slug","super","active","is_deleted","deleted_at","created_at","updated_at"\)`).
+		WithArgs("admin", "admin", true, true, false, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	role, err = repo.EnsureSuper("admin")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), role.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
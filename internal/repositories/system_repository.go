@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// SystemRepository defines the interface for database operations describing the database itself.
+type SystemRepository interface {
+	ServerVersion(ctx context.Context) (string, error)
+	LatestMigration(ctx context.Context) (*models.SchemaMigration, error)
+	RecordMigration(ctx context.Context, migration *models.SchemaMigration) error
+}
+
+type systemRepository struct {
+	db *gorm.DB
+}
+
+// NewSystemRepository creates a new instance of SystemRepository.
+func NewSystemRepository(db *gorm.DB) SystemRepository {
+	return &systemRepository{db: db}
+}
+
+// ServerVersion returns the version string reported by the database server.
+func (r *systemRepository) ServerVersion(ctx context.Context) (string, error) {
+	var version string
+	err := r.db.WithContext(ctx).Raw("SELECT version()").Scan(&version).Error
+	return version, err
+}
+
+// LatestMigration returns the most recently applied migration.
+func (r *systemRepository) LatestMigration(ctx context.Context) (*models.SchemaMigration, error) {
+	var migration models.SchemaMigration
+	if err := r.db.WithContext(ctx).Order("applied_at DESC").Order("id DESC").First(&migration).Error; err != nil {
+		return nil, err
+	}
+	return &migration, nil
+}
+
+// RecordMigration inserts a record of an applied migration.
+func (r *systemRepository) RecordMigration(ctx context.Context, migration *models.SchemaMigration) error {
+	return r.db.WithContext(ctx).Create(migration).Error
+}
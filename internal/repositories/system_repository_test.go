@@ -0,0 +1,42 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-modules-api/internal/repositories"
+)
+
+func TestSystemRepository_ServerVersion(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewSystemRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT version\(\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 16.4"))
+
+	version, err := repo.ServerVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "PostgreSQL 16.4", version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSystemRepository_LatestMigration(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewSystemRepository(gormDB)
+
+	appliedAt := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM "schema_migrations" ORDER BY applied_at DESC,id DESC,"schema_migrations"\."id" LIMIT \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "commit", "applied_at"}).AddRow(3, "v1.4.0", "abc123", appliedAt))
+
+	migration, err := repo.LatestMigration(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), migration.ID)
+	assert.Equal(t, "v1.4.0", migration.Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRevocationRepository defines the interface for database operations related to token revocations.
+type TokenRevocationRepository interface {
+	Save(ctx context.Context, revocation *models.TokenRevocation) error
+	Revoked(ctx context.Context, tokenID string, subjects map[string]string, issuedAt time.Time, now time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type tokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRevocationRepository creates a new instance of TokenRevocationRepository.
+func NewTokenRevocationRepository(db *gorm.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db: db}
+}
+
+// Save inserts the revocation, or moves the revocation of the same kind and key to its time and expiry.
+func (r *tokenRevocationRepository) Save(ctx context.Context, revocation *models.TokenRevocation) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "expires_at"}),
+	}).Create(revocation).Error
+}
+
+// Revoked reports whether an unexpired revocation matches the token ID, or one of the subjects
+// of the token, given as kind to key, at or after the time the token was issued.
+func (r *tokenRevocationRepository) Revoked(ctx context.Context, tokenID string, subjects map[string]string, issuedAt time.Time, now time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+	matches := db.Where("kind = ? AND key = ?", models.RevokedToken, tokenID)
+	for _, kind := range []string{models.RevokedUser, models.RevokedHubClient} {
+		if key, ok := subjects[kind]; ok {
+			matches = matches.Or("kind = ? AND key = ? AND revoked_at >= ?", kind, key, issuedAt)
+		}
+	}
+
+	var count int64
+	err := db.Model(&models.TokenRevocation{}).Where("expires_at > ?", now).Where(matches).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired removes the revocations expired before the given time and returns how many were deleted.
+func (r *tokenRevocationRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&models.TokenRevocation{})
+	return result.RowsAffected, result.Error
+}
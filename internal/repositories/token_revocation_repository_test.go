@@ -0,0 +1,53 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+)
+
+func TestTokenRevocationRepository_Revoked(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewTokenRevocationRepository(gormDB)
+
+	issuedAt := time.Now().Add(-time.Minute)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "token_revocations" WHERE expires_at > \$1 AND \(\(kind = \$2 AND key = \$3\) OR \(kind = \$4 AND key = \$5 AND revoked_at >= \$6\) OR \(kind = \$7 AND key = \$8 AND revoked_at >= \$9\)\)`).
+		WithArgs(now, models.RevokedToken, "jti", models.RevokedUser, "7", issuedAt, models.RevokedHubClient, "3", issuedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	revoked, err := repo.Revoked(context.Background(), "jti", map[string]string{
+		models.RevokedUser:      "7",
+		models.RevokedHubClient: "3",
+	}, issuedAt, now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRevocationRepository_SaveMovesExistingRevocation(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewTokenRevocationRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "token_revocations" .* ON CONFLICT \("kind","key"\) DO UPDATE SET "revoked_at"="excluded"\."revoked_at","expires_at"="excluded"\."expires_at"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.Save(context.Background(), &models.TokenRevocation{
+		Kind:      models.RevokedUser,
+		Key:       "7",
+		RevokedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
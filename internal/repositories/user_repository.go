@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go-modules-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRoleNotFound is returned when a user is assigned a role that does not exist.
+var ErrRoleNotFound = errors.New("role not found")
+
+// UserRepository defines the interface for database operations related to users.
+// Users are tenant-owned, so the context must carry their hub client or be tenant.WithoutScope.
+type UserRepository interface {
+	ResourceRepositoryInterface[models.User]
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	CreateWithRoles(ctx context.Context, user *models.User, roleIDs []uint) error
+	UpdateWithRoles(ctx context.Context, user *models.User, roleIDs *[]uint) error
+	UpdatePassword(ctx context.Context, userID uint, hash string) error
+}
+
+type userRepository struct {
+	*ResourceRepository[models.User, *models.User]
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new instance of UserRepository.
+// Users are searched by name or email using a case-insensitive LIKE query.
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{
+		ResourceRepository: NewResourceRepository[models.User](db, "name", "email"),
+		db:                 db,
+	}
+}
+
+// GetByEmail returns the active user with the given email.
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Scopes(scopeNotDeleted).Where("email = ? AND active = ?", email, true).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateWithRoles inserts a user and assigns it the given roles.
+func (r *userRepository) CreateWithRoles(ctx context.Context, user *models.User, roleIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(user).Error; err != nil {
+			return err
+		}
+		return replaceRoles(tx, user, roleIDs)
+	})
+}
+
+// UpdateWithRoles updates the non-zero fields of a user and, unless roleIDs is nil,
+// replaces its roles.
+func (r *userRepository) UpdateWithRoles(ctx context.Context, user *models.User, roleIDs *[]uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit(clause.Associations).Scopes(scopeNotDeleted).Where("id = ?", user.ID).Updates(user).Error
+		if err != nil || roleIDs == nil {
+			return err
+		}
+		return replaceRoles(tx, user, *roleIDs)
+	})
+}
+
+// UpdatePassword sets the password hash of a user.
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	return updatePassword(r.db.WithContext(ctx), userID, hash)
+}
+
+// updatePassword sets the password hash of a user, failing when the user does not exist.
+func updatePassword(db *gorm.DB, userID uint, hash string) error {
+	result := db.Model(&models.User{}).Scopes(scopeNotDeleted).Where("id = ?", userID).Update("password", hash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// replaceRoles sets the roles of a user to the given ones, which must all exist.
+func replaceRoles(tx *gorm.DB, user *models.User, roleIDs []uint) error {
+	roles := []models.Role{}
+	if len(roleIDs) > 0 {
+		if err := tx.Scopes(scopeNotDeleted).Where("id IN ?", roleIDs).Find(&roles).Error; err != nil {
+			return err
+		}
+		if len(roles) != len(uniqueIDs(roleIDs)) {
+			return ErrRoleNotFound
+		}
+	}
+
+	if err := tx.Model(user).Omit("Roles.*").Association("Roles").Replace(roles); err != nil {
+		return err
+	}
+	user.Roles = roles
+	return nil
+}
+
+// uniqueIDs returns the distinct IDs of the list.
+func uniqueIDs(ids []uint) map[uint]struct{} {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return unique
+}
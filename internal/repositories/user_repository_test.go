@@ -0,0 +1,56 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func TestUserRepository_CreateWithRolesUnknownRole(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewUserRepository(gormDB)
+	ctx := tenant.WithHubClientID(context.Background(), 4)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "users"`).
+		WithArgs(4, "Jane Doe", "jane@example.com", "hash", true, false, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "roles" WHERE id IN \(\$1,\$2\) AND is_deleted = \$3`).
+		WithArgs(1, 2, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectRollback()
+
+	user := &models.User{
+		Name:           "Jane Doe",
+		Email:          "jane@example.com",
+		Password:       "hash",
+		BaseAttributes: models.BaseAttributes{Active: true},
+	}
+	err := repo.CreateWithRoles(ctx, user, []uint{1, 2})
+	assert.ErrorIs(t, err, repositories.ErrRoleNotFound)
+	assert.Equal(t, uint(4), user.HubClientID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdatePasswordNotFound(t *testing.T) {
+	gormDB, mock := newTenantDB(t)
+	repo := repositories.NewUserRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "password"=\$1,"updated_at"=\$2 WHERE id = \$3 AND is_deleted = \$4 AND "users"\."hub_client_id" = \$5`).
+		WithArgs("hash", sqlmock.AnyArg(), 7, false, 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.UpdatePassword(tenant.WithHubClientID(context.Background(), 4), 7, "hash")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
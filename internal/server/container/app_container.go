@@ -1,19 +1,33 @@
 package container
 
+import (
+	"go-modules-api/config"
+	"go-modules-api/internal/health"
+	"go-modules-api/internal/models"
+)
+
 type AppContainer struct {
 	Repositories *RepositoriesContainer
 	Services     *ServicesContainer
 	Handlers     *HandlersContainer
+
+	// Health holds the readiness checks; subsystems register theirs here
+	Health *health.Registry
 }
 
 func NewAppContainer() *AppContainer {
+	healthRegistry := health.NewRegistry(config.Env.HealthCheckTimeout)
+	healthRegistry.Register("database", health.Database(config.DB))
+	healthRegistry.Register("migrations", health.Migrations(config.DB, models.Migrated()))
+
 	repositories := NewRepositoriesContainer()
 	services := NewServicesContainer(repositories)
-	handlers := NewHandlersContainer(services)
+	handlers := NewHandlersContainer(services, healthRegistry)
 
 	return &AppContainer{
 		Repositories: repositories,
 		Services:     services,
 		Handlers:     handlers,
+		Health:       healthRegistry,
 	}
 }
@@ -1,20 +1,49 @@
 package container
 
 import (
+	"go-modules-api/config"
+	"go-modules-api/internal/health"
 	"go-modules-api/internal/server/http/handlers"
+	"go-modules-api/utils"
 )
 
 type HandlersContainer struct {
 	HubClientHandler *handlers.HubClientHandler
 	RoleHandler      *handlers.RoleHandler
+	UserHandler      *handlers.UserHandler
+	AuthHandler      *handlers.AuthHandler
+	APIKeyHandler    *handlers.APIKeyHandler
+	OAuthHandler     *handlers.OAuthHandler
+	SessionHandler   *handlers.SessionHandler
+	HealthHandler    *handlers.HealthHandler
+	AdminHandler     *handlers.AdminHandler
+
+	ClientCertificateHandler *handlers.ClientCertificateHandler
 }
 
-func NewHandlersContainer(services *ServicesContainer) *HandlersContainer {
+func NewHandlersContainer(services *ServicesContainer, healthRegistry *health.Registry) *HandlersContainer {
 	hubClientHandler := handlers.NewHubClientHandler(services.HubClientService)
 	roleHandler := handlers.NewRoleHandler(services.RoleService)
+	userHandler := handlers.NewUserHandler(services.UserService)
+	authHandler := handlers.NewAuthHandler(services.AuthService, services.PasswordService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(services.APIKeyService)
+	oauthHandler := handlers.NewOAuthHandler(services.OAuthService)
+	sessionHandler := handlers.NewSessionHandler(services.RevocationService)
+	clientCertificateHandler := handlers.NewClientCertificateHandler(services.ClientCertificateService)
+	healthHandler := handlers.NewHealthHandler(healthRegistry)
+	adminHandler := handlers.NewAdminHandler(utils.LogLevel, services.SystemService, config.Env.Redacted(), utils.Logger.Named("admin"))
 
 	return &HandlersContainer{
 		HubClientHandler: hubClientHandler,
 		RoleHandler:      roleHandler,
+		UserHandler:      userHandler,
+		AuthHandler:      authHandler,
+		APIKeyHandler:    apiKeyHandler,
+		OAuthHandler:     oauthHandler,
+		SessionHandler:   sessionHandler,
+		HealthHandler:    healthHandler,
+		AdminHandler:     adminHandler,
+
+		ClientCertificateHandler: clientCertificateHandler,
 	}
 }
@@ -8,14 +8,50 @@ import (
 type RepositoriesContainer struct {
 	HubClientRepository repositories.HubClientRepository
 	RoleRepository      repositories.RoleRepository
+	UserRepository      repositories.UserRepository
+	APIKeyRepository    repositories.APIKeyRepository
+
+	PasswordResetTokenRepository repositories.PasswordResetTokenRepository
+	TokenRevocationRepository    repositories.TokenRevocationRepository
+	OAuthClientSecretRepository  repositories.OAuthClientSecretRepository
+	OAuthAccessTokenRepository   repositories.OAuthAccessTokenRepository
+	ClientCertificateRepository  repositories.ClientCertificateRepository
+
+	ModulePermissionRepository repositories.ModulePermissionRepository
+
+	IdempotencyKeyRepository repositories.IdempotencyKeyRepository
+	SystemRepository         repositories.SystemRepository
 }
 
 func NewRepositoriesContainer() *RepositoriesContainer {
 	hubClientRepository := repositories.NewHubClientRepository(config.DB)
 	roleRepository := repositories.NewRoleRepository(config.DB)
+	userRepository := repositories.NewUserRepository(config.DB)
+	apiKeyRepository := repositories.NewAPIKeyRepository(config.DB)
+	passwordResetTokenRepository := repositories.NewPasswordResetTokenRepository(config.DB)
+	tokenRevocationRepository := repositories.NewTokenRevocationRepository(config.DB)
+	oauthClientSecretRepository := repositories.NewOAuthClientSecretRepository(config.DB)
+	oauthAccessTokenRepository := repositories.NewOAuthAccessTokenRepository(config.DB)
+	clientCertificateRepository := repositories.NewClientCertificateRepository(config.DB)
+	modulePermissionRepository := repositories.NewModulePermissionRepository(config.DB)
+	idempotencyKeyRepository := repositories.NewIdempotencyKeyRepository(config.DB)
+	systemRepository := repositories.NewSystemRepository(config.DB)
 
 	return &RepositoriesContainer{
 		HubClientRepository: hubClientRepository,
 		RoleRepository:      roleRepository,
+		UserRepository:      userRepository,
+		APIKeyRepository:    apiKeyRepository,
+
+		PasswordResetTokenRepository: passwordResetTokenRepository,
+		TokenRevocationRepository:    tokenRevocationRepository,
+		OAuthClientSecretRepository:  oauthClientSecretRepository,
+		OAuthAccessTokenRepository:   oauthAccessTokenRepository,
+		ClientCertificateRepository:  clientCertificateRepository,
+
+		ModulePermissionRepository: modulePermissionRepository,
+
+		IdempotencyKeyRepository: idempotencyKeyRepository,
+		SystemRepository:         systemRepository,
 	}
 }
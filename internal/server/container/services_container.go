@@ -1,20 +1,102 @@
 package container
 
 import (
+	"go-modules-api/config"
 	"go-modules-api/internal/services"
+	"go-modules-api/utils"
 )
 
 type ServicesContainer struct {
-	HubClientService services.HubClientService
-	RoleService      services.RoleService
+	HubClientService  services.HubClientService
+	RoleService       services.RoleService
+	UserService       services.UserService
+	PasswordService   services.PasswordService
+	TokenService      services.TokenService
+	RevocationService services.RevocationService
+	AuthService       services.AuthService
+	APIKeyService     services.APIKeyService
+	OAuthService      services.OAuthService
+
+	ClientCertificateService services.ClientCertificateService
+
+	AuthorizationService services.AuthorizationService
+	TenantService        services.TenantService
+
+	IdempotencyService services.IdempotencyService
+	SystemService      services.SystemService
 }
 
 func NewServicesContainer(repositories *RepositoriesContainer) *ServicesContainer {
 	hubClientService := services.NewHubClientService(repositories.HubClientRepository)
 	roleService := services.NewRoleService(repositories.RoleRepository)
+	userService := services.NewUserService(repositories.UserRepository, repositories.RoleRepository)
+	tokenService := services.NewTokenService(config.JWT, config.Env.JwtIssuer, config.Env.JwtAudience)
+	revocationService := services.NewRevocationService(
+		repositories.TokenRevocationRepository,
+		repositories.UserRepository,
+		repositories.HubClientRepository,
+		max(config.Env.JwtAccessTTL, config.Env.JwtRefreshTTL),
+	)
+	passwordService := services.NewPasswordService(
+		repositories.UserRepository,
+		repositories.PasswordResetTokenRepository,
+		revocationService,
+		newPasswordResetNotifier(),
+		config.Env.PasswordResetTTL,
+	)
+	authorizationService := services.NewAuthorizationService(repositories.RoleRepository, repositories.ModulePermissionRepository)
+	authService := services.NewAuthService(repositories.UserRepository, tokenService, revocationService, config.Env.JwtAccessTTL, config.Env.JwtRefreshTTL)
+	apiKeyService := services.NewAPIKeyService(repositories.APIKeyRepository, repositories.HubClientRepository, authorizationService)
+	oauthService := services.NewOAuthService(
+		repositories.OAuthClientSecretRepository,
+		repositories.OAuthAccessTokenRepository,
+		repositories.HubClientRepository,
+		authorizationService,
+		config.Env.OAuthTokenTTL,
+	)
+	clientCertificateService := services.NewClientCertificateService(repositories.ClientCertificateRepository, repositories.HubClientRepository, authorizationService)
+	tenantService := services.NewTenantService(repositories.HubClientRepository)
+	idempotencyService := services.NewIdempotencyService(repositories.IdempotencyKeyRepository, config.Env.IdempotencyTTL)
+	systemService := services.NewSystemService(repositories.SystemRepository)
 
 	return &ServicesContainer{
-		HubClientService: hubClientService,
-		RoleService:      roleService,
+		HubClientService:  hubClientService,
+		RoleService:       roleService,
+		UserService:       userService,
+		PasswordService:   passwordService,
+		TokenService:      tokenService,
+		RevocationService: revocationService,
+		AuthService:       authService,
+		APIKeyService:     apiKeyService,
+		OAuthService:      oauthService,
+
+		ClientCertificateService: clientCertificateService,
+
+		AuthorizationService: authorizationService,
+		TenantService:        tenantService,
+
+		IdempotencyService: idempotencyService,
+		SystemService:      systemService,
+	}
+}
+
+// newPasswordResetNotifier emails reset links when SMTP is configured. Otherwise resets are only
+// logged, without their token, and can't be completed.
+func newPasswordResetNotifier() services.PasswordResetNotifier {
+	log := utils.Logger.Named("password_reset")
+	if config.Env.SmtpHost == "" {
+		log.Warn("Password reset links can't be delivered, set SMTP_HOST")
+		return services.LogPasswordResetNotifier{Logger: log}
+	}
+	if config.Env.PasswordResetURL == "" || config.Env.SmtpFrom == "" {
+		log.Fatal("PASSWORD_RESET_URL and SMTP_FROM are required with SMTP_HOST")
 	}
+	return services.NewSMTPPasswordResetNotifier(
+		config.Env.SmtpHost,
+		config.Env.SmtpPort,
+		config.Env.SmtpUsername,
+		config.Env.SmtpPassword,
+		config.Env.SmtpFrom,
+		config.Env.PasswordResetURL,
+	)
 }
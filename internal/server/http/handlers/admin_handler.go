@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http/pprof"
+	"slices"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+	"go-modules-api/internal/version"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AdminHandler handles the operational endpoints of the API
+type AdminHandler struct {
+	level  zap.AtomicLevel
+	system services.SystemService
+	config map[string]interface{}
+	log    *zap.Logger
+}
+
+// NewAdminHandler creates a new AdminHandler changing the given log level and reporting the
+// given configuration, which must already be redacted
+func NewAdminHandler(level zap.AtomicLevel, system services.SystemService, config map[string]interface{}, log *zap.Logger) *AdminHandler {
+	return &AdminHandler{level: level, system: system, config: config, log: log}
+}
+
+// Info handles GET /admin/info
+func (h *AdminHandler) Info(c *fiber.Ctx) error {
+	uptime := version.Uptime()
+
+	var routes []dto.RouteInfoDTO
+	for _, route := range c.App().GetRoutes(true) {
+		routes = append(routes, dto.RouteInfoDTO{Method: route.Method, Path: route.Path, Name: route.Name})
+	}
+	slices.SortFunc(routes, func(a, b dto.RouteInfoDTO) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+
+	return c.JSON(dto.SystemInfoDTO{
+		Build:         version.Get(),
+		StartedAt:     version.StartedAt(),
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Config:        h.config,
+		Database:      h.system.Database(c.UserContext()),
+		Routes:        routes,
+	})
+}
+
+// LogLevel handles GET /admin/log_level
+func (h *AdminHandler) LogLevel(c *fiber.Ctx) error {
+	return c.JSON(dto.LogLevelDTO{Level: h.level.Level().String()})
+}
+
+// SetLogLevel handles PUT /admin/log_level
+// The level applies to every logger of the process until it restarts or changes again.
+func (h *AdminHandler) SetLogLevel(c *fiber.Ctx) error {
+	var payload dto.LogLevelDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	level, err := zapcore.ParseLevel(payload.Level)
+	if err != nil {
+		return exceptions.BadRequest("Invalid log level", nil).Response(c)
+	}
+
+	previous := h.level.Level()
+	h.level.SetLevel(level)
+	utils.ContextLogger(c.UserContext(), h.log).Warn("Log level changed",
+		zap.Stringer("from", previous),
+		zap.Stringer("to", level),
+	)
+
+	return c.JSON(dto.LogLevelDTO{Level: level.String()})
+}
+
+// Profile handles GET /debug/pprof/*, serving the index or the named runtime profile
+func (h *AdminHandler) Profile(c *fiber.Ctx) error {
+	switch name := c.Params("*"); name {
+	case "":
+		// The index links to the profiles relative to its own path
+		if !strings.HasSuffix(c.Path(), "/") {
+			return c.Redirect(c.Path()+"/", fiber.StatusFound)
+		}
+		return adaptor.HTTPHandlerFunc(pprof.Index)(c)
+	case "cmdline":
+		return adaptor.HTTPHandlerFunc(pprof.Cmdline)(c)
+	case "profile":
+		return adaptor.HTTPHandlerFunc(pprof.Profile)(c)
+	case "symbol":
+		return adaptor.HTTPHandlerFunc(pprof.Symbol)(c)
+	case "trace":
+		return adaptor.HTTPHandlerFunc(pprof.Trace)(c)
+	default:
+		return adaptor.HTTPHandler(pprof.Handler(name))(c)
+	}
+}
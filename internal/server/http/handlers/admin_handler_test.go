@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"go-modules-api/internal/dto"
+)
+
+// MockSystemService is a mock implementation of the SystemService interface
+type MockSystemService struct {
+	mock.Mock
+}
+
+func (m *MockSystemService) Database(ctx context.Context) dto.DatabaseInfoDTO {
+	args := m.Called()
+	return args.Get(0).(dto.DatabaseInfoDTO)
+}
+
+func TestAdminHandler_Info(t *testing.T) {
+	mockService := new(MockSystemService)
+	handler := NewAdminHandler(zap.NewAtomicLevel(), mockService, map[string]interface{}{"DB_PASSWORD": "[REDACTED]"}, zap.NewNop())
+
+	app := fiber.New()
+	app.Get("/admin/info", handler.Info)
+	app.Post("/users", func(c *fiber.Ctx) error { return nil })
+
+	mockService.On("Database").Return(dto.DatabaseInfoDTO{
+		ServerVersion: "PostgreSQL 16.4",
+		Migration:     &dto.MigrationInfoDTO{Version: "v1.4.0"},
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/info", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body dto.SystemInfoDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.NotEmpty(t, body.Build.GoVersion)
+	assert.Equal(t, "[REDACTED]", body.Config["DB_PASSWORD"])
+	assert.Equal(t, "PostgreSQL 16.4", body.Database.ServerVersion)
+	assert.Equal(t, "v1.4.0", body.Database.Migration.Version)
+	assert.Contains(t, body.Routes, dto.RouteInfoDTO{Method: "GET", Path: "/admin/info"})
+	assert.Contains(t, body.Routes, dto.RouteInfoDTO{Method: "POST", Path: "/users"})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminHandler_LogLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.WarnLevel)
+	handler := NewAdminHandler(level, new(MockSystemService), nil, zap.NewNop())
+
+	app := fiber.New()
+	app.Get("/admin/log_level", handler.LogLevel)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/log_level", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body dto.LogLevelDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "warn", body.Level)
+}
+
+func TestAdminHandler_SetLogLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	handler := NewAdminHandler(level, new(MockSystemService), nil, zap.NewNop())
+
+	app := fiber.New()
+	app.Put("/admin/log_level", handler.SetLogLevel)
+
+	req := httptest.NewRequest("PUT", "/admin/log_level", strings.NewReader(`{"level":"debug"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+
+	req = httptest.NewRequest("PUT", "/admin/log_level", strings.NewReader(`{"level":"verbose"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
+func TestAdminHandler_Profile(t *testing.T) {
+	handler := NewAdminHandler(zap.NewAtomicLevel(), new(MockSystemService), nil, zap.NewNop())
+
+	app := fiber.New()
+	app.Get("/admin/debug/pprof/*", handler.Profile)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/debug/pprof", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/admin/debug/pprof/", resp.Header.Get("Location"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/debug/pprof/", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "goroutine")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/debug/pprof/goroutine?debug=1", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "goroutine profile")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/debug/pprof/unknown", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles HTTP requests for the API keys of a hub client
+type APIKeyHandler struct {
+	service services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(service services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// List handles GET /hub_clients/:id/api_keys
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	keys, err := h.service.List(c.UserContext(), uint(hubClientID))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(keys)
+}
+
+// Create handles POST /hub_clients/:id/api_keys
+// The plaintext key is only returned by this response.
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var payload dto.CreateAPIKeyDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	key, plaintext, err := h.service.Generate(c.UserContext(), uint(hubClientID), &payload)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	setNoStore(c)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"api_key": key, "key": plaintext})
+}
+
+// Rotate handles POST /hub_clients/:id/api_keys/:key_id/rotate
+func (h *APIKeyHandler) Rotate(c *fiber.Ctx) error {
+	hubClientID, keyID, ok := apiKeyParams(c)
+	if !ok {
+		return invalidID(c)
+	}
+
+	key, plaintext, err := h.service.Rotate(c.UserContext(), hubClientID, keyID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	setNoStore(c)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"api_key": key, "key": plaintext})
+}
+
+// Revoke handles DELETE /hub_clients/:id/api_keys/:key_id
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	hubClientID, keyID, ok := apiKeyParams(c)
+	if !ok {
+		return invalidID(c)
+	}
+
+	if err := h.service.Revoke(c.UserContext(), hubClientID, keyID); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// apiKeyParams parses the hub client and key IDs of the route
+func apiKeyParams(c *fiber.Ctx) (uint, uint, bool) {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return 0, 0, false
+	}
+	keyID, err := c.ParamsInt("key_id")
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint(hubClientID), uint(keyID), true
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+)
+
+// MockAPIKeyService is a mock implementation of the APIKeyService interface.
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context, hubClientID uint) ([]models.APIKey, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Generate(ctx context.Context, hubClientID uint, payload *dto.CreateAPIKeyDTO) (*models.APIKey, string, error) {
+	args := m.Called(hubClientID, payload)
+	return args.Get(0).(*models.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, hubClientID uint, id uint) error {
+	args := m.Called(hubClientID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Rotate(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, string, error) {
+	args := m.Called(hubClientID, id)
+	return args.Get(0).(*models.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/hub_clients/:id/api_keys", handler.Create)
+
+	payload := &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: []string{"roles:read"}}
+	key := &models.APIKey{BaseID: models.BaseID{ID: 1}, HubClientID: 2, Name: "Integration", Prefix: "gma_0123456789ab", Scopes: payload.Scopes}
+	mockService.On("Generate", uint(2), payload).Return(key, "gma_0123456789ab_secret", nil)
+
+	req := httptest.NewRequest("POST", "/hub_clients/2/api_keys", strings.NewReader(`{"name":"Integration","scopes":["roles:read"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var body struct {
+		APIKey map[string]interface{} `json:"api_key"`
+		Key    string                 `json:"key"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "gma_0123456789ab_secret", body.Key)
+	assert.Equal(t, "gma_0123456789ab", body.APIKey["prefix"])
+	assert.NotContains(t, body.APIKey, "key_hash")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_InvalidScope(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/hub_clients/:id/api_keys", handler.Create)
+
+	req := httptest.NewRequest("POST", "/hub_clients/2/api_keys", strings.NewReader(`{"name":"Integration","scopes":["roles:delete"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	mockService.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandler(mockService)
+
+	app := fiber.New()
+	app.Delete("/hub_clients/:id/api_keys/:key_id", handler.Revoke)
+
+	mockService.On("Revoke", uint(2), uint(5)).Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/hub_clients/2/api_keys/5", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"strconv"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/server/http/middleware"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthHandler handles HTTP requests for authentication
+type AuthHandler struct {
+	service   services.AuthService
+	passwords services.PasswordService
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(service services.AuthService, passwords services.PasswordService) *AuthHandler {
+	return &AuthHandler{service: service, passwords: passwords}
+}
+
+// Login handles POST /auth/login
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var payload dto.LoginDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	user, tokens, err := h.service.Login(c.UserContext(), payload.Email, payload.Password)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	setNoStore(c)
+	return c.JSON(fiber.Map{"user": user, "auth": tokens})
+}
+
+// Refresh handles POST /auth/refresh
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var payload dto.RefreshTokenDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	user, tokens, err := h.service.Refresh(c.UserContext(), payload.RefreshToken)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	setNoStore(c)
+	return c.JSON(fiber.Map{"user": user, "auth": tokens})
+}
+
+// Logout handles POST /auth/logout
+// It revokes the access token of the request and the refresh token of the body, if any.
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return exceptions.Forbidden("This operation requires a user token", nil).Response(c)
+	}
+
+	var payload dto.LogoutDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&payload); err != nil {
+			return exceptions.BadRequest("Invalid request body", nil).Response(c)
+		}
+	}
+
+	if err := h.service.Logout(c.UserContext(), claims, payload.RefreshToken); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ChangePassword handles PUT /auth/password for the authenticated user
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return exceptions.Forbidden("This operation requires a user token", nil).Response(c)
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return exceptions.Unauthorized("Invalid or expired token", nil).Response(c)
+	}
+
+	var payload dto.ChangePasswordDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if err := h.passwords.Change(c.UserContext(), uint(userID), payload.CurrentPassword, payload.NewPassword); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ForgotPassword handles POST /auth/password/forgot
+// It answers 202 whether or not the email belongs to a user.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var payload dto.ForgotPasswordDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if err := h.passwords.RequestReset(c.UserContext(), payload.Email); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// ResetPassword handles POST /auth/password/reset
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var payload dto.ResetPasswordDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if err := h.passwords.Reset(c.UserContext(), payload.Token, payload.NewPassword); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/server/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheControl is sent with every cacheable GET response.
+// It is set from the HTTP_CACHE_CONTROL config.
+var CacheControl = "private, no-cache"
+
+// weakETag builds a weak validator from the given parts
+func weakETag(parts ...interface{}) string {
+	return `W/"` + hashParts(parts...) + `"`
+}
+
+// hashParts returns a short hex digest of the given parts
+func hashParts(parts ...interface{}) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(hash, "%v\x00", part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// callerETag builds a weak validator from the given parts and the caller of the request,
+// so validators computed for one caller or hub client never match the responses of another
+func callerETag(c *fiber.Ctx, parts ...interface{}) string {
+	hubClientID, _ := middleware.GetHubClientID(c)
+	return weakETag(append([]interface{}{middleware.Principal(c), hubClientID}, parts...)...)
+}
+
+// notModified sets the caching headers of a GET response and reports whether
+// the request preconditions allow answering 304 Not Modified.
+// A zero lastModified omits the Last-Modified header.
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, CacheControl)
+	// Responses depend on the caller and the hub client it acts for
+	c.Vary(fiber.HeaderAuthorization, middleware.HubClientHeader)
+	if !lastModified.IsZero() {
+		lastModified = lastModified.UTC().Truncate(time.Second)
+		c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since (RFC 9110, section 13.2.2)
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	if ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.After(since)
+	}
+
+	return false
+}
+
+// etagMatches performs the weak comparison used by If-None-Match
+func etagMatches(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// setNoStore prevents caching of responses carrying credentials, as required for tokens by
+// RFC 6749 section 5.1. It also keeps them out of the idempotency store.
+func setNoStore(c *fiber.Ctx) {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+}
+
+// sendNotModified answers 304 keeping the validators already set on the response
+func sendNotModified(c *fiber.Ctx) error {
+	c.Status(fiber.StatusNotModified)
+	return nil
+}
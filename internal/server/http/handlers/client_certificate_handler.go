@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientCertificateHandler handles HTTP requests for the mutual TLS certificates of a hub client
+type ClientCertificateHandler struct {
+	service services.ClientCertificateService
+}
+
+// NewClientCertificateHandler creates a new ClientCertificateHandler
+func NewClientCertificateHandler(service services.ClientCertificateService) *ClientCertificateHandler {
+	return &ClientCertificateHandler{service: service}
+}
+
+// List handles GET /hub_clients/:id/certificates
+func (h *ClientCertificateHandler) List(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	certificates, err := h.service.List(c.UserContext(), uint(hubClientID))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(certificates)
+}
+
+// Create handles POST /hub_clients/:id/certificates
+func (h *ClientCertificateHandler) Create(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var payload dto.CreateClientCertificateDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	certificate, err := h.service.Create(c.UserContext(), uint(hubClientID), &payload)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(certificate)
+}
+
+// Revoke handles DELETE /hub_clients/:id/certificates/:certificate_id
+func (h *ClientCertificateHandler) Revoke(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	certificateID, err := c.ParamsInt("certificate_id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.service.Revoke(c.UserContext(), uint(hubClientID), uint(certificateID)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+)
+
+// MockClientCertificateService is a mock implementation of the ClientCertificateService interface.
+type MockClientCertificateService struct {
+	mock.Mock
+}
+
+func (m *MockClientCertificateService) List(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.ClientCertificate), args.Error(1)
+}
+
+func (m *MockClientCertificateService) Create(ctx context.Context, hubClientID uint, payload *dto.CreateClientCertificateDTO) (*models.ClientCertificate, error) {
+	args := m.Called(hubClientID, payload)
+	return args.Get(0).(*models.ClientCertificate), args.Error(1)
+}
+
+func (m *MockClientCertificateService) Revoke(ctx context.Context, hubClientID uint, id uint) error {
+	args := m.Called(hubClientID, id)
+	return args.Error(0)
+}
+
+func (m *MockClientCertificateService) Authenticate(ctx context.Context, certificate *x509.Certificate, verified bool) (*models.ClientCertificate, error) {
+	args := m.Called(certificate, verified)
+	return args.Get(0).(*models.ClientCertificate), args.Error(1)
+}
+
+func TestClientCertificateHandler_Create(t *testing.T) {
+	mockService := new(MockClientCertificateService)
+	handler := NewClientCertificateHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/hub_clients/:id/certificates", handler.Create)
+
+	payload := &dto.CreateClientCertificateDTO{Name: "Partner gateway", Subject: "CN=partner,O=Acme", Scopes: []string{"roles:read"}}
+	mockService.On("Create", uint(2), payload).
+		Return(&models.ClientCertificate{BaseID: models.BaseID{ID: 1}, HubClientID: 2, Name: payload.Name, Subject: payload.Subject, Scopes: payload.Scopes}, nil)
+
+	req := httptest.NewRequest("POST", "/hub_clients/2/certificates", strings.NewReader(`{"name":"Partner gateway","subject":"CN=partner,O=Acme","scopes":["roles:read"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestClientCertificateHandler_Create_RequiresFingerprintOrSubject(t *testing.T) {
+	mockService := new(MockClientCertificateService)
+	handler := NewClientCertificateHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/hub_clients/:id/certificates", handler.Create)
+
+	for _, body := range []string{
+		`{"name":"Partner gateway","scopes":["roles:read"]}`,
+		`{"name":"Partner gateway","fingerprint":"not-a-fingerprint","scopes":["roles:read"]}`,
+	} {
+		req := httptest.NewRequest("POST", "/hub_clients/2/certificates", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	}
+
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"go-modules-api/internal/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// Live handles GET /healthz
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Ready handles GET /readyz
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	report := h.registry.Run(c.UserContext())
+	if !report.Up() {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(report)
+}
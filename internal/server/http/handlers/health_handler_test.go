@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/health"
+)
+
+func TestHealthHandler_Live(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error { return errors.New("down") })
+	handler := NewHealthHandler(registry)
+
+	app := fiber.New()
+	app.Get("/healthz", handler.Live)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/healthz", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	var databaseErr error
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error { return databaseErr })
+	registry.Register("migrations", func(ctx context.Context) error { return nil })
+	handler := NewHealthHandler(registry)
+
+	app := fiber.New()
+	app.Get("/readyz", handler.Ready)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, health.StatusUp, report.Status)
+	assert.Len(t, report.Checks, 2)
+
+	databaseErr = errors.New("connection refused")
+	resp, err = app.Test(httptest.NewRequest("GET", "/readyz", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	report = health.Report{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+	assert.Equal(t, health.StatusUp, report.Checks["migrations"].Status)
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -11,7 +14,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
 )
 
 // MockHubClientService is a mock implementation of the HubClientService interface
@@ -19,37 +24,46 @@ type MockHubClientService struct {
 	mock.Mock
 }
 
-func (m *MockHubClientService) PaginateHubClients(params dto.PaginatedHubClientDTO) ([]models.HubClient, int64, error) {
+func (m *MockHubClientService) WithContext(ctx context.Context) services.BaseServiceInterface[models.HubClient] {
+	return m
+}
+
+func (m *MockHubClientService) Paginate(params dto.PaginatedDTO) ([]models.HubClient, int64, error) {
 	args := m.Called(params)
 	return args.Get(0).([]models.HubClient), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockHubClientService) ListHubClients(search string, active *bool, sortField string, sortOrder string) ([]models.HubClient, error) {
-	args := m.Called(search, active, sortField, sortOrder)
+func (m *MockHubClientService) List(params dto.ListDTO) ([]models.HubClient, error) {
+	args := m.Called(params)
 	return args.Get(0).([]models.HubClient), args.Error(1)
 }
 
-func (m *MockHubClientService) GetHubClientByID(id uint) (*models.HubClient, error) {
+func (m *MockHubClientService) LastModified(params dto.ListDTO) (int64, time.Time, error) {
+	args := m.Called(params)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockHubClientService) GetByID(id uint) (*models.HubClient, error) {
 	args := m.Called(id)
 	return args.Get(0).(*models.HubClient), args.Error(1)
 }
 
-func (m *MockHubClientService) CreateHubClient(hubClient *models.HubClient) error {
+func (m *MockHubClientService) Create(hubClient *models.HubClient) error {
 	args := m.Called(hubClient)
 	return args.Error(0)
 }
 
-func (m *MockHubClientService) UpdateHubClient(hubClient *models.HubClient) error {
+func (m *MockHubClientService) Update(hubClient *models.HubClient) error {
 	args := m.Called(hubClient)
 	return args.Error(0)
 }
 
-func (m *MockHubClientService) DeleteHubClient(id uint) error {
+func (m *MockHubClientService) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockHubClientService) SoftDeleteHubClient(hubClient *models.HubClient) error {
+func (m *MockHubClientService) SoftDelete(hubClient *models.HubClient) error {
 	args := m.Called(hubClient)
 	return args.Error(0)
 }
@@ -59,9 +73,9 @@ func TestHubClientHandler_PaginateHubClients(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/hub_clients/paginate", handler.PaginateHubClients)
+	app.Get("/hub_clients/paginate", handler.Paginate)
 
-	params := dto.PaginatedHubClientDTO{
+	params := dto.PaginatedDTO{
 		Search:    "Client",
 		SortField: "id",
 		SortOrder: "asc",
@@ -71,7 +85,8 @@ func TestHubClientHandler_PaginateHubClients(t *testing.T) {
 	mockClients := []models.HubClient{{BaseID: models.BaseID{ID: 1}, Name: "Client1"}, {BaseID: models.BaseID{ID: 2}, Name: "Client2"}}
 	mockTotal := int64(2)
 
-	mockService.On("PaginateHubClients", params).Return(mockClients, mockTotal, nil)
+	mockService.On("LastModified", dto.ListDTO{Search: params.Search}).Return(mockTotal, time.Now(), nil)
+	mockService.On("Paginate", params).Return(mockClients, mockTotal, nil)
 
 	req := httptest.NewRequest("GET", "/hub_clients/paginate?search=Client&sort_field=id&sort_order=asc&page=1&page_size=10", nil)
 	resp, err := app.Test(req, -1)
@@ -84,7 +99,7 @@ func TestHubClientHandler_ListHubClients(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/hub_clients", handler.ListHubClients)
+	app.Get("/hub_clients", handler.List)
 
 	search := "Client"
 	active := true
@@ -92,7 +107,8 @@ func TestHubClientHandler_ListHubClients(t *testing.T) {
 	sortOrder := "asc"
 	mockClients := []models.HubClient{{BaseID: models.BaseID{ID: 1}, Name: "Client1"}}
 
-	mockService.On("ListHubClients", search, &active, sortField, sortOrder).Return(mockClients, nil)
+	mockService.On("LastModified", dto.ListDTO{Search: search, Active: &active}).Return(int64(1), time.Now(), nil)
+	mockService.On("List", dto.ListDTO{Search: search, Active: &active, SortField: sortField, SortOrder: sortOrder}).Return(mockClients, nil)
 
 	req := httptest.NewRequest("GET", "/hub_clients?search=Client&active=true&sort_field=id&sort_order=asc", nil)
 	resp, err := app.Test(req, -1)
@@ -105,12 +121,12 @@ func TestHubClientHandler_GetHubClientByID(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/hub_clients/:id", handler.GetHubClientByID)
+	app.Get("/hub_clients/:id", handler.GetByID)
 
 	mockID := uint(1)
 	mockClient := &models.HubClient{BaseID: models.BaseID{ID: mockID}, Name: "Client1"}
 
-	mockService.On("GetHubClientByID", mockID).Return(mockClient, nil)
+	mockService.On("GetByID", mockID).Return(mockClient, nil)
 
 	req := httptest.NewRequest("GET", "/hub_clients/1", nil)
 	resp, err := app.Test(req, -1)
@@ -123,10 +139,10 @@ func TestHubClientHandler_CreateHubClient(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Post("/hub_clients", handler.CreateHubClient)
+	app.Post("/hub_clients", handler.Create)
 
 	mockClient := &models.HubClient{Name: "Client1", ExternalID: "1"}
-	mockService.On("CreateHubClient", mockClient).Return(nil)
+	mockService.On("Create", mockClient).Return(nil)
 
 	payload := `{"name":"Client1","external_id": "1"}`
 	req := httptest.NewRequest("POST", "/hub_clients", strings.NewReader(payload))
@@ -141,13 +157,13 @@ func TestHubClientHandler_UpdateHubClient(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Put("/hub_clients/:id", handler.UpdateHubClient)
+	app.Put("/hub_clients/:id", handler.Update)
 
 	mockID := uint(1)
 	mockClient := &models.HubClient{BaseID: models.BaseID{ID: mockID}, Name: "Client1", ExternalID: "1"}
-	mockService.On("GetHubClientByID", mockID).Return(mockClient, nil)
+	mockService.On("GetByID", mockID).Return(mockClient, nil)
 	updatedClient := &models.HubClient{BaseID: models.BaseID{ID: mockID}, Name: "Client1", ExternalID: "1"}
-	mockService.On("UpdateHubClient", updatedClient).Return(nil)
+	mockService.On("Update", updatedClient).Return(nil)
 
 	payload := `{"name":"Client1","external_id": "1"}`
 	req := httptest.NewRequest("PUT", "/hub_clients/1", strings.NewReader(payload))
@@ -162,12 +178,12 @@ func TestHubClientHandler_DeleteHubClient(t *testing.T) {
 	handler := NewHubClientHandler(mockService)
 
 	app := fiber.New()
-	app.Delete("/hub_clients/:id", handler.SoftDeleteHubClient)
+	app.Delete("/hub_clients/:id", handler.SoftDelete)
 
 	mockID := uint(1)
 	mockClient := &models.HubClient{BaseID: models.BaseID{ID: mockID}, Name: "Client1"}
-	mockService.On("GetHubClientByID", mockID).Return(mockClient, nil)
-	mockService.On("SoftDeleteHubClient", mockClient).Return(nil)
+	mockService.On("GetByID", mockID).Return(mockClient, nil)
+	mockService.On("SoftDelete", mockClient).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/hub_clients/1", nil)
 	resp, err := app.Test(req, -1)
@@ -176,3 +192,26 @@ func TestHubClientHandler_DeleteHubClient(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestHubClientHandler_ListHubClients_InvalidSortField(t *testing.T) {
+	mockService := new(MockHubClientService)
+	handler := NewHubClientHandler(mockService)
+
+	app := fiber.New()
+	app.Get("/hub_clients", handler.List)
+
+	req := httptest.NewRequest("GET", "/hub_clients?sort_field=slug", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, exceptions.ProblemContentType, resp.Header.Get("Content-Type"))
+
+	var problem exceptions.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, fiber.StatusUnprocessableEntity, problem.Status)
+	assert.Equal(t, "Validation failed", problem.Detail)
+	assert.Equal(t, "/hub_clients?sort_field=slug", problem.Instance)
+	assert.Len(t, problem.Errors, 1)
+
+	mockService.AssertNotCalled(t, "List", mock.Anything)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/server/http/middleware"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OAuthHandler handles the OAuth2 client credentials endpoints and the client secrets of a hub client
+type OAuthHandler struct {
+	service services.OAuthService
+}
+
+// NewOAuthHandler creates a new OAuthHandler
+func NewOAuthHandler(service services.OAuthService) *OAuthHandler {
+	return &OAuthHandler{service: service}
+}
+
+// Token handles POST /oauth/token
+// Only the client_credentials grant is supported. The client authenticates with HTTP Basic
+// or with client_id and client_secret in the form body.
+func (h *OAuthHandler) Token(c *fiber.Ctx) error {
+	var payload dto.OAuthTokenRequestDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return oauthErrorResponse(c, services.OAuthError(fiber.StatusBadRequest, services.OAuthInvalidRequest, "Invalid request body"))
+	}
+
+	if payload.GrantType != "client_credentials" {
+		return oauthErrorResponse(c, services.OAuthError(fiber.StatusBadRequest, services.OAuthUnsupportedGrantType, "Only the client_credentials grant is supported"))
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, payload.OAuthClientDTO)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	token, err := h.service.IssueToken(c.UserContext(), clientID, clientSecret, payload.Scope)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	setNoStore(c)
+	return c.JSON(token)
+}
+
+// Introspect handles POST /oauth/introspect
+func (h *OAuthHandler) Introspect(c *fiber.Ctx) error {
+	var payload dto.OAuthTokenActionDTO
+	if err := c.BodyParser(&payload); err != nil || payload.Token == "" {
+		return oauthErrorResponse(c, services.OAuthError(fiber.StatusBadRequest, services.OAuthInvalidRequest, "The token parameter is required"))
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, payload.OAuthClientDTO)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	introspection, err := h.service.Introspect(c.UserContext(), clientID, clientSecret, payload.Token)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	setNoStore(c)
+	return c.JSON(introspection)
+}
+
+// Revoke handles POST /oauth/revoke
+// It answers 200 for unknown tokens too, as required by RFC 7009.
+func (h *OAuthHandler) Revoke(c *fiber.Ctx) error {
+	var payload dto.OAuthTokenActionDTO
+	if err := c.BodyParser(&payload); err != nil || payload.Token == "" {
+		return oauthErrorResponse(c, services.OAuthError(fiber.StatusBadRequest, services.OAuthInvalidRequest, "The token parameter is required"))
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, payload.OAuthClientDTO)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	if err := h.service.RevokeToken(c.UserContext(), clientID, clientSecret, payload.Token); err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// ListSecrets handles GET /hub_clients/:id/client_secrets
+func (h *OAuthHandler) ListSecrets(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	secrets, err := h.service.ListSecrets(c.UserContext(), uint(hubClientID))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(secrets)
+}
+
+// CreateSecret handles POST /hub_clients/:id/client_secrets
+// The plaintext secret is only returned by this response.
+func (h *OAuthHandler) CreateSecret(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var payload dto.CreateOAuthClientSecretDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	secret, plaintext, err := h.service.CreateSecret(c.UserContext(), uint(hubClientID), &payload)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	setNoStore(c)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"client_secret": secret, "secret": plaintext})
+}
+
+// RevokeSecret handles DELETE /hub_clients/:id/client_secrets/:secret_id
+func (h *OAuthHandler) RevokeSecret(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	secretID, err := c.ParamsInt("secret_id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.service.RevokeSecret(c.UserContext(), uint(hubClientID), uint(secretID)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// clientCredentials returns the client credentials of the HTTP Basic header, whose parts are
+// form-encoded as in RFC 6749 section 2.3.1, or else those of the body
+func clientCredentials(c *fiber.Ctx, body dto.OAuthClientDTO) (string, string, error) {
+	clientID, clientSecret, ok, err := middleware.BasicCredentials(c)
+	if !ok {
+		return body.ClientID, body.ClientSecret, nil
+	}
+	if err != nil {
+		return "", "", services.OAuthError(fiber.StatusUnauthorized, services.OAuthInvalidClient, "Invalid Basic authorization header")
+	}
+	return clientID, clientSecret, nil
+}
+
+// oauthErrorResponse writes an error in the RFC 6749 format expected by OAuth2 clients,
+// hiding any error that is not an APIException behind a server_error
+func oauthErrorResponse(c *fiber.Ctx, err error) error {
+	var apiErr *exceptions.APIException
+	if !errors.As(err, &apiErr) || apiErr.Status >= fiber.StatusInternalServerError {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":             "server_error",
+			"error_description": "An unexpected error occurred",
+		})
+	}
+
+	if apiErr.Code == services.OAuthInvalidClient {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	}
+	setNoStore(c)
+	return c.Status(apiErr.Status).JSON(fiber.Map{
+		"error":             apiErr.Code,
+		"error_description": apiErr.Message,
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// MockOAuthService is a mock implementation of the OAuthService interface
+type MockOAuthService struct {
+	mock.Mock
+}
+
+func (m *MockOAuthService) ListSecrets(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.OAuthClientSecret), args.Error(1)
+}
+
+func (m *MockOAuthService) CreateSecret(ctx context.Context, hubClientID uint, payload *dto.CreateOAuthClientSecretDTO) (*models.OAuthClientSecret, string, error) {
+	args := m.Called(hubClientID, payload)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthClientSecret), args.String(1), args.Error(2)
+	}
+	return nil, "", args.Error(2)
+}
+
+func (m *MockOAuthService) RevokeSecret(ctx context.Context, hubClientID uint, id uint) error {
+	args := m.Called(hubClientID, id)
+	return args.Error(0)
+}
+
+func (m *MockOAuthService) IssueToken(ctx context.Context, clientID string, clientSecret string, scope string) (*dto.OAuthTokenDTO, error) {
+	args := m.Called(clientID, clientSecret, scope)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.OAuthTokenDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthService) Introspect(ctx context.Context, clientID string, clientSecret string, token string) (*dto.OAuthIntrospectionDTO, error) {
+	args := m.Called(clientID, clientSecret, token)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.OAuthIntrospectionDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthService) RevokeToken(ctx context.Context, clientID string, clientSecret string, token string) error {
+	args := m.Called(clientID, clientSecret, token)
+	return args.Error(0)
+}
+
+func (m *MockOAuthService) Authenticate(ctx context.Context, token string) (*models.OAuthAccessToken, error) {
+	args := m.Called(token)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthService) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestOAuthHandler_TokenWithBasicAuth(t *testing.T) {
+	mockService := new(MockOAuthService)
+	handler := NewOAuthHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/oauth/token", handler.Token)
+
+	mockService.On("IssueToken", "partner:eu", "gmcs_abc_secret", "roles:read").
+		Return(&dto.OAuthTokenDTO{AccessToken: "gmo_token", TokenType: "Bearer", ExpiresIn: 900, Scope: "roles:read"}, nil)
+
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=client_credentials&scope=roles%3Aread"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("partner%3Aeu:gmcs_abc_secret")))
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	var body dto.OAuthTokenDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "gmo_token", body.AccessToken)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestOAuthHandler_TokenWithBodyCredentials(t *testing.T) {
+	mockService := new(MockOAuthService)
+	handler := NewOAuthHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/oauth/token", handler.Token)
+
+	mockService.On("IssueToken", "partner", "gmcs_abc_secret", "").
+		Return(&dto.OAuthTokenDTO{AccessToken: "gmo_token", TokenType: "Bearer", ExpiresIn: 900}, nil)
+
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=client_credentials&client_id=partner&client_secret=gmcs_abc_secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestOAuthHandler_TokenErrors(t *testing.T) {
+	mockService := new(MockOAuthService)
+	handler := NewOAuthHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/oauth/token", handler.Token)
+
+	mockService.On("IssueToken", "partner", "wrong", "").
+		Return(nil, services.OAuthError(fiber.StatusUnauthorized, services.OAuthInvalidClient, "Client authentication failed"))
+
+	cases := []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{"grant_type=password&client_id=partner&client_secret=wrong", fiber.StatusBadRequest, services.OAuthUnsupportedGrantType},
+		{"grant_type=client_credentials&client_id=partner&client_secret=wrong", fiber.StatusUnauthorized, services.OAuthInvalidClient},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, tc.code, body["error"])
+	}
+}
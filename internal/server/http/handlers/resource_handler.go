@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResourceHooks customises how a ResourceHandler validates and maps payloads for a resource.
+// MapCreate and MapUpdate are required, the validation hooks are optional.
+type ResourceHooks[T any, CreateDTO any, UpdateDTO any] struct {
+	// SortFields lists the values accepted by the sort_field query parameter.
+	SortFields []string
+
+	// ValidateCreate runs after struct validation of a create payload.
+	ValidateCreate func(payload *CreateDTO) []*utils.ValidationError
+	// ValidateUpdate runs after struct validation of an update payload.
+	ValidateUpdate func(id uint, payload *UpdateDTO) []*utils.ValidationError
+
+	// MapCreate builds a new entity from a create payload.
+	MapCreate func(payload *CreateDTO) *T
+	// MapUpdate builds the entity holding the changes of an update payload.
+	MapUpdate func(id uint, payload *UpdateDTO) *T
+}
+
+// ResourceHandler handles the standard CRUD HTTP requests for any resource
+type ResourceHandler[T any, CreateDTO any, UpdateDTO any] struct {
+	service services.BaseServiceInterface[T]
+	hooks   ResourceHooks[T, CreateDTO, UpdateDTO]
+}
+
+// NewResourceHandler creates a new ResourceHandler
+func NewResourceHandler[T any, CreateDTO any, UpdateDTO any](service services.BaseServiceInterface[T], hooks ResourceHooks[T, CreateDTO, UpdateDTO]) *ResourceHandler[T, CreateDTO, UpdateDTO] {
+	return &ResourceHandler[T, CreateDTO, UpdateDTO]{service: service, hooks: hooks}
+}
+
+// Paginate handles GET /<resource>/paginate
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) Paginate(c *fiber.Ctx) error {
+	params := dto.PaginatedDTO{
+		Search:    c.Query("search", ""),
+		Active:    queryBool(c, "active"),
+		SortField: c.Query("sort_field", "id"),
+		SortOrder: strings.ToLower(c.Query("sort_order", "asc")),
+		Page:      c.QueryInt("page", 1),
+		PageSize:  c.QueryInt("page_size", 10),
+	}
+
+	validationErrors := h.validateSortField(params.SortField, utils.ValidateStruct(params))
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if h.collectionNotModified(c, params.Search, params.Active) {
+		return sendNotModified(c)
+	}
+
+	entities, total, err := h.service.WithContext(c.UserContext()).Paginate(params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	meta := utils.GeneratePaginationMeta(total, params.Page, params.PageSize)
+
+	return c.JSON(fiber.Map{"data": entities, "meta": meta})
+}
+
+// List handles GET /<resource> with filtering and sorting
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) List(c *fiber.Ctx) error {
+	params := dto.ListDTO{
+		Search:    c.Query("search", ""),
+		Active:    queryBool(c, "active"),
+		SortField: c.Query("sort_field", "id"),
+		SortOrder: strings.ToLower(c.Query("sort_order", "asc")),
+	}
+
+	validationErrors := h.validateSortField(params.SortField, utils.ValidateStruct(params))
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if h.collectionNotModified(c, params.Search, params.Active) {
+		return sendNotModified(c)
+	}
+
+	entities, err := h.service.WithContext(c.UserContext()).List(params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(entities)
+}
+
+// GetByID handles GET /<resource>/:id
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) GetByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	entity, err := h.service.WithContext(c.UserContext()).GetByID(uint(id))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	// Models tracking updates are validated by UpdatedAt, any other by a hash of their content
+	if timestamped, ok := any(entity).(models.UpdatedAtGetter); ok {
+		updatedAt := timestamped.GetUpdatedAt()
+		if notModified(c, callerETag(c, c.Path(), updatedAt.UnixNano()), updatedAt) {
+			return sendNotModified(c)
+		}
+		return c.JSON(entity)
+	}
+
+	body, err := c.App().Config().JSONEncoder(entity)
+	if err != nil {
+		return err
+	}
+	if notModified(c, callerETag(c, c.Path(), hashParts(string(body))), time.Time{}) {
+		return sendNotModified(c)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
+
+// Create handles POST /<resource>
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) Create(c *fiber.Ctx) error {
+	var payload CreateDTO
+
+	// Parse JSON body
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	// Validate input
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) == 0 && h.hooks.ValidateCreate != nil {
+		validationErrors = h.hooks.ValidateCreate(&payload)
+	}
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	// Create model from DTO
+	entity := h.hooks.MapCreate(&payload)
+
+	// Call service
+	if err := h.service.WithContext(c.UserContext()).Create(entity); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entity)
+}
+
+// Update handles PUT /<resource>/:id
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var payload UpdateDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	// Validate input
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) == 0 && h.hooks.ValidateUpdate != nil {
+		validationErrors = h.hooks.ValidateUpdate(uint(id), &payload)
+	}
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	service := h.service.WithContext(c.UserContext())
+	if _, err := service.GetByID(uint(id)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	// Update entity with new values
+	entity := h.hooks.MapUpdate(uint(id), &payload)
+
+	// Call service
+	if err := service.Update(entity); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(entity)
+}
+
+// SoftDelete handles DELETE /<resource>/:id
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) SoftDelete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	service := h.service.WithContext(c.UserContext())
+	entity, err := service.GetByID(uint(id))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	if err := service.SoftDelete(entity); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// collectionNotModified sets the caching headers of a list response from the count and latest
+// update of the filtered records, so an unchanged collection is answered without being loaded.
+// Errors are ignored and leave the response uncached.
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) collectionNotModified(c *fiber.Ctx, search string, active *bool) bool {
+	count, lastModified, err := h.service.WithContext(c.UserContext()).LastModified(dto.ListDTO{Search: search, Active: active})
+	if err != nil {
+		return false
+	}
+	return notModified(c, callerETag(c, c.OriginalURL(), count, lastModified.UnixNano()), lastModified)
+}
+
+// validateSortField appends a oneof error when the sort field is not allowed for the resource.
+func (h *ResourceHandler[T, CreateDTO, UpdateDTO]) validateSortField(sortField string, validationErrors []*utils.ValidationError) []*utils.ValidationError {
+	if sortField == "" || slices.Contains(h.hooks.SortFields, sortField) {
+		return validationErrors
+	}
+	return append(validationErrors, &utils.ValidationError{
+		Field:         "SortField",
+		Tag:           "oneof",
+		Value:         sortField,
+		AllowedValues: h.hooks.SortFields,
+	})
+}
+
+// queryBool parses an optional boolean query parameter, ignoring invalid values.
+func queryBool(c *fiber.Ctx, key string) *bool {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// validationFailed writes the validation errors response.
+func validationFailed(c *fiber.Ctx, validationErrors []*utils.ValidationError) error {
+	return exceptions.ValidationFailed(validationErrors).Response(c)
+}
+
+// invalidID writes the response for a malformed :id route parameter.
+func invalidID(c *fiber.Ctx) error {
+	return exceptions.BadRequest("Invalid ID format", fiber.Map{"field": "id", "value": c.Params("id")}).Response(c)
+}
+
+// handleServiceError writes the APIException carried by err, hiding any other error behind a generic 500.
+func handleServiceError(c *fiber.Ctx, err error) error {
+	var apiErr *exceptions.APIException
+	if errors.As(err, &apiErr) {
+		return apiErr.Response(c)
+	}
+	return exceptions.InternalServerError("An unexpected error occurred", nil).Response(c)
+}
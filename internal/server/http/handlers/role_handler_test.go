@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +15,8 @@ import (
 
 	"go-modules-api/internal/dto"
 	"go-modules-api/internal/models"
+	"go-modules-api/internal/server/http/middleware"
+	"go-modules-api/internal/services"
 )
 
 // MockRoleService is a mock implementation of the RoleService interface.
@@ -19,37 +24,46 @@ type MockRoleService struct {
 	mock.Mock
 }
 
-func (m *MockRoleService) PaginateRoles(params dto.PaginatedRoleDTO) ([]models.Role, int64, error) {
+func (m *MockRoleService) WithContext(ctx context.Context) services.BaseServiceInterface[models.Role] {
+	return m
+}
+
+func (m *MockRoleService) Paginate(params dto.PaginatedDTO) ([]models.Role, int64, error) {
 	args := m.Called(params)
 	return args.Get(0).([]models.Role), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockRoleService) ListRoles(search string, active *bool, sortField, sortOrder string) ([]models.Role, error) {
-	args := m.Called(search, active, sortField, sortOrder)
+func (m *MockRoleService) List(params dto.ListDTO) ([]models.Role, error) {
+	args := m.Called(params)
 	return args.Get(0).([]models.Role), args.Error(1)
 }
 
-func (m *MockRoleService) GetRoleByID(id uint) (*models.Role, error) {
+func (m *MockRoleService) LastModified(params dto.ListDTO) (int64, time.Time, error) {
+	args := m.Called(params)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockRoleService) GetByID(id uint) (*models.Role, error) {
 	args := m.Called(id)
 	return args.Get(0).(*models.Role), args.Error(1)
 }
 
-func (m *MockRoleService) CreateRole(role *models.Role) error {
+func (m *MockRoleService) Create(role *models.Role) error {
 	args := m.Called(role)
 	return args.Error(0)
 }
 
-func (m *MockRoleService) UpdateRole(role *models.Role) error {
+func (m *MockRoleService) Update(role *models.Role) error {
 	args := m.Called(role)
 	return args.Error(0)
 }
 
-func (m *MockRoleService) DeleteRole(id uint) error {
+func (m *MockRoleService) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockRoleService) SoftDeleteRole(role *models.Role) error {
+func (m *MockRoleService) SoftDelete(role *models.Role) error {
 	args := m.Called(role)
 	return args.Error(0)
 }
@@ -59,10 +73,10 @@ func TestRoleHandler_PaginateRoles(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/roles/paginate", handler.PaginateRoles)
+	app.Get("/roles/paginate", handler.Paginate)
 
 	// Update the expected arguments to match defaults:
-	expectedParams := dto.PaginatedRoleDTO{
+	expectedParams := dto.PaginatedDTO{
 		Page:      1,  // Handler defaults
 		PageSize:  10, // Handler defaults
 		Search:    "",
@@ -78,7 +92,8 @@ func TestRoleHandler_PaginateRoles(t *testing.T) {
 	mockTotal := int64(2)
 
 	// The mock now matches the actual parameters
-	mockService.On("PaginateRoles", expectedParams).Return(mockRoles, mockTotal, nil)
+	mockService.On("LastModified", dto.ListDTO{}).Return(mockTotal, time.Now(), nil)
+	mockService.On("Paginate", expectedParams).Return(mockRoles, mockTotal, nil)
 
 	req := httptest.NewRequest("GET", "/roles/paginate", nil)
 	resp, err := app.Test(req, -1)
@@ -91,7 +106,7 @@ func TestRoleHandler_ListRoles(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/roles", handler.ListRoles)
+	app.Get("/roles", handler.List)
 
 	search := "admin"
 	active := true
@@ -101,7 +116,8 @@ func TestRoleHandler_ListRoles(t *testing.T) {
 		{BaseID: models.BaseID{ID: 1}, Name: "Admin"},
 	}
 
-	mockService.On("ListRoles", search, &active, sortField, sortOrder).Return(mockRoles, nil)
+	mockService.On("LastModified", dto.ListDTO{Search: search, Active: &active}).Return(int64(1), time.Now(), nil)
+	mockService.On("List", dto.ListDTO{Search: search, Active: &active, SortField: sortField, SortOrder: sortOrder}).Return(mockRoles, nil)
 
 	req := httptest.NewRequest("GET", "/roles?search=admin&active=true&sort_field=id&sort_order=asc", nil)
 	resp, err := app.Test(req, -1)
@@ -114,12 +130,12 @@ func TestRoleHandler_GetRoleByID(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Get("/roles/:id", handler.GetRoleByID)
+	app.Get("/roles/:id", handler.GetByID)
 
 	mockID := uint(1)
 	mockRole := &models.Role{BaseID: models.BaseID{ID: mockID}, Name: "Admin"}
 
-	mockService.On("GetRoleByID", mockID).Return(mockRole, nil)
+	mockService.On("GetByID", mockID).Return(mockRole, nil)
 
 	req := httptest.NewRequest("GET", "/roles/1", nil)
 	resp, err := app.Test(req, -1)
@@ -132,7 +148,7 @@ func TestRoleHandler_CreateRole(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Post("/roles", handler.CreateRole)
+	app.Post("/roles", handler.Create)
 
 	role := &models.Role{
 		Name: "Admin",
@@ -143,7 +159,7 @@ func TestRoleHandler_CreateRole(t *testing.T) {
 	}
 
 	// Make sure the mock expects the 'Active' field to be true
-	mockService.On("CreateRole", role).Return(nil)
+	mockService.On("Create", role).Return(nil)
 
 	payload := `{"name":"Admin","slug":"admin"}`
 	req := httptest.NewRequest("POST", "/roles", strings.NewReader(payload))
@@ -158,16 +174,16 @@ func TestRoleHandler_UpdateRole(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Put("/roles/:id", handler.UpdateRole)
+	app.Put("/roles/:id", handler.Update)
 
 	mockID := uint(1)
 	existingRole := &models.Role{BaseID: models.BaseID{ID: mockID}, Name: "Admin", Slug: "admin"}
 	updatedRole := &models.Role{BaseID: models.BaseID{ID: mockID}, Name: "Admin", Slug: "admin"}
 
 	// Mock existing role retrieval
-	mockService.On("GetRoleByID", mockID).Return(existingRole, nil)
+	mockService.On("GetByID", mockID).Return(existingRole, nil)
 	// Mock update call
-	mockService.On("UpdateRole", updatedRole).Return(nil)
+	mockService.On("Update", updatedRole).Return(nil)
 
 	payload := `{"name":"Admin","slug":"admin"}`
 	req := httptest.NewRequest("PUT", "/roles/1", strings.NewReader(payload))
@@ -183,15 +199,15 @@ func TestRoleHandler_SoftDeleteRole(t *testing.T) {
 	handler := NewRoleHandler(mockService)
 
 	app := fiber.New()
-	app.Delete("/roles/:id", handler.SoftDeleteRole)
+	app.Delete("/roles/:id", handler.SoftDelete)
 
 	mockID := uint(1)
 	existingRole := &models.Role{BaseID: models.BaseID{ID: mockID}, Name: "Admin"}
 
 	// Mock existing role retrieval
-	mockService.On("GetRoleByID", mockID).Return(existingRole, nil)
+	mockService.On("GetByID", mockID).Return(existingRole, nil)
 	// Mock soft-delete call
-	mockService.On("SoftDeleteRole", existingRole).Return(nil)
+	mockService.On("SoftDelete", existingRole).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/roles/1", nil)
 	resp, err := app.Test(req, -1)
@@ -200,3 +216,85 @@ func TestRoleHandler_SoftDeleteRole(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestRoleHandler_GetRoleByID_NotModified(t *testing.T) {
+	mockService := new(MockRoleService)
+	handler := NewRoleHandler(mockService)
+
+	app := fiber.New()
+	app.Get("/roles/:id", handler.GetByID)
+
+	mockID := uint(1)
+	updatedAt := time.Date(2025, 2, 1, 18, 8, 26, 0, time.UTC)
+	mockRole := &models.Role{BaseID: models.BaseID{ID: mockID}, Name: "Admin", BaseTimestamps: models.BaseTimestamps{UpdatedAt: updatedAt}}
+
+	mockService.On("GetByID", mockID).Return(mockRole, nil)
+
+	req := httptest.NewRequest("GET", "/roles/1", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "Sat, 01 Feb 2025 18:08:26 GMT", resp.Header.Get("Last-Modified"))
+
+	req = httptest.NewRequest("GET", "/roles/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/roles/1", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Feb 2025 18:08:26 GMT")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+}
+
+func TestRoleHandler_ListRoles_NotModified(t *testing.T) {
+	mockService := new(MockRoleService)
+	handler := NewRoleHandler(mockService)
+
+	app := fiber.New()
+	app.Get("/roles", handler.List)
+
+	mockService.On("LastModified", dto.ListDTO{}).Return(int64(3), time.Now(), nil)
+
+	req := httptest.NewRequest("GET", "/roles", nil)
+	req.Header.Set("If-None-Match", "*")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	mockService.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestRoleHandler_ListRoles_ETagPerHubClient(t *testing.T) {
+	mockService := new(MockRoleService)
+	handler := NewRoleHandler(mockService)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		hubClientID, _ := strconv.ParseUint(c.Get(middleware.HubClientHeader), 10, 64)
+		c.Locals(middleware.HubClientIDKey, uint(hubClientID))
+		return c.Next()
+	})
+	app.Get("/roles", handler.List)
+
+	lastModified := time.Now()
+	mockService.On("LastModified", dto.ListDTO{}).Return(int64(3), lastModified, nil)
+	mockService.On("List", mock.Anything).Return([]models.Role{}, nil)
+
+	etag := func(hubClientID string) string {
+		req := httptest.NewRequest("GET", "/roles", nil)
+		req.Header.Set(middleware.HubClientHeader, hubClientID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, "Authorization, X-Hub-Client", resp.Header.Get("Vary"))
+		return resp.Header.Get("ETag")
+	}
+
+	// Same URL, count and modification time, but another tenant
+	assert.NotEqual(t, etag("1"), etag("2"))
+	assert.Equal(t, etag("1"), etag("1"))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"go-modules-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionHandler handles the revocation of every session of a user or hub client
+type SessionHandler struct {
+	service services.RevocationService
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(service services.RevocationService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// RevokeUser handles DELETE /users/:id/sessions
+func (h *SessionHandler) RevokeUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.service.RevokeUser(c.UserContext(), uint(userID)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeHubClient handles DELETE /hub_clients/:id/sessions
+func (h *SessionHandler) RevokeHubClient(c *fiber.Ctx) error {
+	hubClientID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.service.RevokeHubClient(c.UserContext(), uint(hubClientID)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
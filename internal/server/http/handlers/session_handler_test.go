@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+)
+
+// MockRevocationService is a mock implementation of the RevocationService interface
+type MockRevocationService struct {
+	mock.Mock
+}
+
+func (m *MockRevocationService) RevokeToken(ctx context.Context, claims *services.Claims) error {
+	args := m.Called(claims)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) RevokeUser(ctx context.Context, userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) RevokeHubClient(ctx context.Context, hubClientID uint) error {
+	args := m.Called(hubClientID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) Check(ctx context.Context, claims *services.Claims) error {
+	args := m.Called(claims)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestSessionHandler_RevokeUser(t *testing.T) {
+	mockService := new(MockRevocationService)
+	handler := NewSessionHandler(mockService)
+
+	app := fiber.New()
+	app.Delete("/users/:id/sessions", handler.RevokeUser)
+
+	mockService.On("RevokeUser", uint(7)).Return(nil)
+	mockService.On("RevokeUser", uint(8)).Return(exceptions.NotFound("Record not found", nil))
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/7/sessions", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/users/8/sessions", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSessionHandler_RevokeHubClient(t *testing.T) {
+	mockService := new(MockRevocationService)
+	handler := NewSessionHandler(mockService)
+
+	app := fiber.New()
+	app.Delete("/hub_clients/:id/sessions", handler.RevokeHubClient)
+
+	mockService.On("RevokeHubClient", uint(4)).Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/hub_clients/4/sessions", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/hub_clients/abc/sessions", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler handles HTTP requests for users.
+// Listing and deletion are the standard resource ones, creation and updates also assign roles.
+type UserHandler struct {
+	*ResourceHandler[models.User, dto.CreateUserDTO, dto.UpdateUserDTO]
+	service services.UserService
+}
+
+// NewUserHandler creates a new UserHandler
+func NewUserHandler(service services.UserService) *UserHandler {
+	return &UserHandler{
+		ResourceHandler: NewResourceHandler[models.User](service, ResourceHooks[models.User, dto.CreateUserDTO, dto.UpdateUserDTO]{
+			SortFields: []string{"id", "name", "email", "active", "created_at", "updated_at"},
+			MapCreate: func(payload *dto.CreateUserDTO) *models.User {
+				return &models.User{
+					Name:  payload.Name,
+					Email: payload.Email,
+					BaseAttributes: models.BaseAttributes{
+						Active: true,
+					},
+				}
+			},
+			MapUpdate: func(id uint, payload *dto.UpdateUserDTO) *models.User {
+				return &models.User{
+					BaseID: models.BaseID{ID: id},
+					Name:   payload.Name,
+					Email:  payload.Email,
+				}
+			},
+		}),
+		service: service,
+	}
+}
+
+// Create handles POST /users
+// The user is created in the hub client of the request.
+func (h *UserHandler) Create(c *fiber.Ctx) error {
+	var payload dto.CreateUserDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	user := h.hooks.MapCreate(&payload)
+	if err := h.service.CreateUser(c.UserContext(), user, payload.Password, payload.RoleIDs); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// Update handles PUT /users/:id
+// The roles are replaced when role_ids is sent and kept otherwise.
+func (h *UserHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var payload dto.UpdateUserDTO
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.BadRequest("Invalid request body", nil).Response(c)
+	}
+
+	validationErrors := utils.ValidateStruct(payload)
+	if len(validationErrors) > 0 {
+		return validationFailed(c, validationErrors)
+	}
+
+	if _, err := h.service.WithContext(c.UserContext()).GetByID(uint(id)); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	user := h.hooks.MapUpdate(uint(id), &payload)
+	if err := h.service.UpdateUser(c.UserContext(), user, payload.RoleIDs); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.JSON(user)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// MockUserService is a mock implementation of the UserService interface
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) WithContext(ctx context.Context) services.BaseServiceInterface[models.User] {
+	return m
+}
+
+func (m *MockUserService) Paginate(params dto.PaginatedDTO) ([]models.User, int64, error) {
+	args := m.Called(params)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserService) List(params dto.ListDTO) ([]models.User, error) {
+	args := m.Called(params)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserService) LastModified(params dto.ListDTO) (int64, time.Time, error) {
+	args := m.Called(params)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockUserService) GetByID(id uint) (*models.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) Create(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserService) Update(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserService) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SoftDelete(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, user *models.User, password string, roleIDs []uint) error {
+	args := m.Called(user, password, roleIDs)
+	return args.Error(0)
+}
+
+func (m *MockUserService) UpdateUser(ctx context.Context, user *models.User, roleIDs *[]uint) error {
+	args := m.Called(user, roleIDs)
+	return args.Error(0)
+}
+
+func TestUserHandler_CreateUser(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/users", handler.Create)
+
+	expected := &models.User{
+		Name:           "Jane Doe",
+		Email:          "jane@example.com",
+		BaseAttributes: models.BaseAttributes{Active: true},
+	}
+	mockService.On("CreateUser", expected, "correct horse", []uint{1, 2}).Return(nil)
+
+	payload := `{"name":"Jane Doe","email":"jane@example.com","password":"correct horse","role_ids":[1,2]}`
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_CreateUser_ShortPassword(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService)
+
+	app := fiber.New()
+	app.Post("/users", handler.Create)
+
+	payload := `{"name":"Jane Doe","email":"jane@example.com","password":"short"}`
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserHandler_UpdateUser_KeepsRoles(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService)
+
+	app := fiber.New()
+	app.Put("/users/:id", handler.Update)
+
+	mockID := uint(1)
+	mockService.On("GetByID", mockID).Return(&models.User{BaseID: models.BaseID{ID: mockID}}, nil)
+	expected := &models.User{BaseID: models.BaseID{ID: mockID}, Name: "Jane Roe"}
+	mockService.On("UpdateUser", expected, (*[]uint)(nil)).Return(nil)
+
+	payload := `{"name":"Jane Roe"}`
+	req := httptest.NewRequest("PUT", "/users/1", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
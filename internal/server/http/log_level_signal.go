@@ -0,0 +1,11 @@
+//go:build !windows
+
+package http
+
+import (
+	"os"
+	"syscall"
+)
+
+// logLevelSignals toggle the debug log level
+var logLevelSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,6 @@
+package http
+
+import "os"
+
+// logLevelSignals is empty, Windows has no SIGUSR1
+var logLevelSignals []os.Signal
@@ -0,0 +1,297 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// ClaimsKey is the fiber.Ctx locals key holding the *services.Claims of a user token
+	ClaimsKey = "claims"
+	// APIKeyKey is the fiber.Ctx locals key holding the *models.APIKey of an API key request
+	APIKeyKey = "api_key"
+	// OAuthTokenKey is the fiber.Ctx locals key holding the *models.OAuthAccessToken of an OAuth2 request
+	OAuthTokenKey = "oauth_token"
+	// ClientCertificateKey is the fiber.Ctx locals key holding the *models.ClientCertificate of a mutual TLS request
+	ClientCertificateKey = "client_certificate"
+	// HubClientIDKey is the fiber.Ctx locals key holding the ID of the hub client a request is bound to
+	HubClientIDKey = "hub_client_id"
+)
+
+// Credential authenticates a bearer token and stores the caller in the request locals.
+// It reports handled as false when the token is not of its kind, so the next credential is tried.
+type Credential func(c *fiber.Ctx, token string) (handled bool, err error)
+
+// AuthConfig configures the Authenticate middleware
+type AuthConfig struct {
+	// Enabled turns authentication off when false, e.g. for local development
+	Enabled bool
+	// PublicRoutes lists "METHOD /path" patterns reachable without a token.
+	// The method may be omitted and a trailing * matches any path suffix.
+	PublicRoutes []string
+}
+
+// Authenticate requires a bearer token accepted by one of the credentials on every request
+// except public routes. Credentials are tried in order. Requests authenticated by ClientCertificate
+// need no token, and a token sent with them must be bound to the hub client of the certificate.
+func Authenticate(cfg AuthConfig, credentials ...Credential) fiber.Handler {
+	public := parseRoutePatterns(cfg.PublicRoutes)
+
+	return func(c *fiber.Ctx) error {
+		if !cfg.Enabled || matchRoute(public, c.Method(), c.Path()) >= 0 {
+			return c.Next()
+		}
+
+		certificate := GetClientCertificate(c)
+		token, ok := bearerToken(c)
+		if !ok && certificate != nil {
+			return c.Next()
+		}
+		if !ok {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api"`)
+			return exceptions.Unauthorized("Missing bearer token", nil)
+		}
+
+		for _, credential := range credentials {
+			handled, err := credential(c, token)
+			if !handled {
+				continue
+			}
+			if err != nil {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api", error="invalid_token"`)
+				return err
+			}
+			if certificate != nil && !boundTo(c, certificate.HubClientID) {
+				return exceptions.Forbidden("The token and the client certificate belong to different hub clients", nil)
+			}
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api", error="invalid_token"`)
+		return exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+}
+
+// ClientCertificate authenticates requests sent over mutual TLS with a registered client certificate
+// and binds them to its hub client. Connections without a certificate, or with an unknown one, are
+// left to the bearer token credentials.
+func ClientCertificate(certificates services.ClientCertificateService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state := c.Context().TLSConnectionState()
+		if state == nil || len(state.PeerCertificates) == 0 {
+			return c.Next()
+		}
+
+		certificate, err := certificates.Authenticate(c.UserContext(), state.PeerCertificates[0], len(state.VerifiedChains) > 0)
+		if err != nil {
+			return err
+		}
+		if certificate != nil {
+			c.Locals(ClientCertificateKey, certificate)
+			c.Locals(HubClientIDKey, certificate.HubClientID)
+		}
+		return c.Next()
+	}
+}
+
+// JWTCredential accepts user access tokens that have not been revoked
+func JWTCredential(tokens services.TokenService, revocations services.RevocationService) Credential {
+	return func(c *fiber.Ctx, token string) (bool, error) {
+		claims, err := tokens.Verify(token, services.AccessToken)
+		if err != nil {
+			return true, err
+		}
+		if err := revocations.Check(c.UserContext(), claims); err != nil {
+			return true, err
+		}
+		c.Locals(ClaimsKey, claims)
+		return true, nil
+	}
+}
+
+// APIKeyCredential accepts hub client API keys and binds the request to their hub client
+func APIKeyCredential(keys services.APIKeyService) Credential {
+	return func(c *fiber.Ctx, token string) (bool, error) {
+		if !services.IsAPIKey(token) {
+			return false, nil
+		}
+		key, err := keys.Authenticate(c.UserContext(), token)
+		if err != nil {
+			return true, err
+		}
+		c.Locals(APIKeyKey, key)
+		c.Locals(HubClientIDKey, key.HubClientID)
+		return true, nil
+	}
+}
+
+// OAuthCredential accepts OAuth2 access tokens and binds the request to their hub client
+func OAuthCredential(oauth services.OAuthService) Credential {
+	return func(c *fiber.Ctx, token string) (bool, error) {
+		if !services.IsOAuthToken(token) {
+			return false, nil
+		}
+		accessToken, err := oauth.Authenticate(c.UserContext(), token)
+		if err != nil {
+			return true, err
+		}
+		c.Locals(OAuthTokenKey, accessToken)
+		c.Locals(HubClientIDKey, accessToken.HubClientID)
+		return true, nil
+	}
+}
+
+// scoped is implemented by the machine credentials limited by scopes
+type scoped interface {
+	HasScope(scope string) bool
+}
+
+// RequireScope restricts API key, OAuth2 and client certificate requests to credentials granting
+// "<resource>:read" for safe methods and "<resource>:write" otherwise. User tokens are not affected.
+func RequireScope(resource string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var key scoped
+		if apiKey := GetAPIKey(c); apiKey != nil {
+			key = apiKey
+		} else if token := GetOAuthToken(c); token != nil {
+			key = token
+		} else if certificate := GetClientCertificate(c); certificate != nil && GetClaims(c) == nil {
+			key = certificate
+		} else {
+			return c.Next()
+		}
+
+		scope := resource + ":write"
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			scope = resource + ":read"
+		}
+
+		if !key.HasScope(scope) {
+			return exceptions.Forbidden("Credential is missing the required scope", fiber.Map{"scope": scope})
+		}
+		return c.Next()
+	}
+}
+
+// RequireUser rejects requests not authenticated with a user token, including every request
+// when authentication is disabled
+func RequireUser() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if GetAPIKey(c) != nil || GetOAuthToken(c) != nil {
+			return exceptions.Forbidden("This operation requires a user token", nil)
+		}
+		if GetClaims(c) == nil {
+			if GetClientCertificate(c) != nil {
+				return exceptions.Forbidden("This operation requires a user token", nil)
+			}
+			return exceptions.Unauthorized("This operation requires a user token", nil)
+		}
+		return c.Next()
+	}
+}
+
+// GetClaims returns the claims of the authenticated request, or nil
+func GetClaims(c *fiber.Ctx) *services.Claims {
+	claims, _ := c.Locals(ClaimsKey).(*services.Claims)
+	return claims
+}
+
+// GetAPIKey returns the API key of the authenticated request, or nil
+func GetAPIKey(c *fiber.Ctx) *models.APIKey {
+	key, _ := c.Locals(APIKeyKey).(*models.APIKey)
+	return key
+}
+
+// GetOAuthToken returns the OAuth2 access token of the authenticated request, or nil
+func GetOAuthToken(c *fiber.Ctx) *models.OAuthAccessToken {
+	token, _ := c.Locals(OAuthTokenKey).(*models.OAuthAccessToken)
+	return token
+}
+
+// GetClientCertificate returns the client certificate of a mutual TLS request, or nil
+func GetClientCertificate(c *fiber.Ctx) *models.ClientCertificate {
+	certificate, _ := c.Locals(ClientCertificateKey).(*models.ClientCertificate)
+	return certificate
+}
+
+// Principal identifies the credential that authenticated the request, e.g. "user:7" or
+// "api_key:3", or returns an empty string for anonymous requests
+func Principal(c *fiber.Ctx) string {
+	switch {
+	case GetAPIKey(c) != nil:
+		return "api_key:" + strconv.FormatUint(uint64(GetAPIKey(c).ID), 10)
+	case GetOAuthToken(c) != nil:
+		return "client_secret:" + strconv.FormatUint(uint64(GetOAuthToken(c).ClientSecretID), 10)
+	case GetClaims(c) != nil:
+		return "user:" + GetClaims(c).Subject
+	case GetClientCertificate(c) != nil:
+		return "client_certificate:" + strconv.FormatUint(uint64(GetClientCertificate(c).ID), 10)
+	}
+	return ""
+}
+
+// GetHubClientID returns the ID of the hub client the request is bound to, if any
+func GetHubClientID(c *fiber.Ctx) (uint, bool) {
+	id, ok := c.Locals(HubClientIDKey).(uint)
+	return id, ok
+}
+
+// boundTo reports whether the credentials of the request are bound to the hub client,
+// through their own hub client or the hub_client_id claim of a user token
+func boundTo(c *fiber.Ctx, hubClientID uint) bool {
+	if id, ok := GetHubClientID(c); ok && id != hubClientID {
+		return false
+	}
+	if claims := GetClaims(c); claims != nil && claims.HubClientID != nil && *claims.HubClientID != hubClientID {
+		return false
+	}
+	return true
+}
+
+// BasicCredentials returns the user and password of an HTTP Basic authorization header, URL decoded
+// as RFC 6749 requires for client credentials. ok is false without a Basic header, and err is set
+// when the header is malformed.
+func BasicCredentials(c *fiber.Ctx) (user string, password string, ok bool, err error) {
+	scheme, encoded, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false, nil
+	}
+
+	invalid := errors.New("invalid Basic authorization header")
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", true, invalid
+	}
+	rawUser, rawPassword, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", true, invalid
+	}
+	if user, err = url.QueryUnescape(rawUser); err != nil {
+		return "", "", true, invalid
+	}
+	if password, err = url.QueryUnescape(rawPassword); err != nil {
+		return "", "", true, invalid
+	}
+	return user, password, true, nil
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	header := c.Get(fiber.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
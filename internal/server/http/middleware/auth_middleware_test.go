@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// MockAPIKeyService is a mock implementation of the APIKeyService interface.
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context, hubClientID uint) ([]models.APIKey, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Generate(ctx context.Context, hubClientID uint, payload *dto.CreateAPIKeyDTO) (*models.APIKey, string, error) {
+	args := m.Called(hubClientID, payload)
+	return args.Get(0).(*models.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, hubClientID uint, id uint) error {
+	args := m.Called(hubClientID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Rotate(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, string, error) {
+	args := m.Called(hubClientID, id)
+	return args.Get(0).(*models.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	args := m.Called(key)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// locals returns a handler storing the given locals, standing in for the authentication middleware
+func locals(values map[string]interface{}) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for key, value := range values {
+			c.Locals(key, value)
+		}
+		return c.Next()
+	}
+}
+
+// ok answers 200, standing in for a route handler
+func ok(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// status sends a request to the app and returns its status code
+func status(t *testing.T, app *fiber.App, method, target string, headers map[string]string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func userClaims() *services.Claims {
+	return &services.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "7"}}
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	keys := new(MockAPIKeyService)
+	keys.On("Authenticate", "gma_valid").Return(&models.APIKey{HubClientID: 3, Scopes: []string{"roles:read"}}, nil)
+	keys.On("Authenticate", "gma_revoked").Return(nil, exceptions.Unauthorized("Invalid or expired API key", nil))
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Authenticate(AuthConfig{Enabled: true, PublicRoutes: []string{"GET /public"}}, APIKeyCredential(keys)))
+	app.Get("/public", ok)
+	app.Get("/roles", RequireScope("roles"), func(c *fiber.Ctx) error {
+		hubClientID, _ := GetHubClientID(c)
+		assert.Equal(t, uint(3), hubClientID)
+		return ok(c)
+	})
+	app.Post("/roles", RequireScope("roles"), ok)
+
+	bearer := func(token string) map[string]string {
+		return map[string]string{fiber.HeaderAuthorization: "Bearer " + token}
+	}
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/public", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/roles", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/roles", bearer("gma_revoked")))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/roles", bearer("unknown")))
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", bearer("gma_valid")))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "POST", "/roles", bearer("gma_valid")))
+}
+
+func TestAuthenticate_Disabled(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Authenticate(AuthConfig{Enabled: false}))
+	app.Get("/roles", ok)
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+}
+
+func TestRequireUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		locals map[string]interface{}
+		want   int
+	}{
+		{name: "user token", locals: map[string]interface{}{ClaimsKey: userClaims()}, want: fiber.StatusOK},
+		{name: "user token over mutual TLS", locals: map[string]interface{}{ClaimsKey: userClaims(), ClientCertificateKey: &models.ClientCertificate{}}, want: fiber.StatusOK},
+		{name: "api key", locals: map[string]interface{}{APIKeyKey: &models.APIKey{}}, want: fiber.StatusForbidden},
+		{name: "oauth token", locals: map[string]interface{}{OAuthTokenKey: &models.OAuthAccessToken{}}, want: fiber.StatusForbidden},
+		{name: "client certificate", locals: map[string]interface{}{ClientCertificateKey: &models.ClientCertificate{}}, want: fiber.StatusForbidden},
+		{name: "anonymous", locals: map[string]interface{}{}, want: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/auth/password", locals(tt.locals), RequireUser(), ok)
+
+			assert.Equal(t, tt.want, status(t, app, "GET", "/auth/password", nil))
+		})
+	}
+}
+
+// MockOAuthService is a mock implementation of the OAuthService interface, only authenticating tokens.
+type MockOAuthService struct {
+	services.OAuthService
+	mock.Mock
+}
+
+func (m *MockOAuthService) Authenticate(ctx context.Context, token string) (*models.OAuthAccessToken, error) {
+	args := m.Called(token)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestAuthenticate_OAuthToken(t *testing.T) {
+	oauth := new(MockOAuthService)
+	oauth.On("Authenticate", "gmo_valid").Return(&models.OAuthAccessToken{HubClientID: 4, Scopes: []string{"roles:write"}}, nil)
+	oauth.On("Authenticate", "gmo_expired").Return(nil, exceptions.Unauthorized("Invalid or expired token", nil))
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Authenticate(AuthConfig{Enabled: true}, APIKeyCredential(new(MockAPIKeyService)), OAuthCredential(oauth)))
+	app.Post("/roles", RequireScope("roles"), func(c *fiber.Ctx) error {
+		hubClientID, _ := GetHubClientID(c)
+		assert.Equal(t, uint(4), hubClientID)
+		return ok(c)
+	})
+	app.Post("/users", RequireScope("users"), ok)
+	app.Post("/auth/logout", RequireUser(), ok)
+
+	bearer := func(token string) map[string]string {
+		return map[string]string{fiber.HeaderAuthorization: "Bearer " + token}
+	}
+	assert.Equal(t, fiber.StatusOK, status(t, app, "POST", "/roles", bearer("gmo_valid")))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "POST", "/users", bearer("gmo_valid")))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "POST", "/auth/logout", bearer("gmo_valid")))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "POST", "/roles", bearer("gmo_expired")))
+}
+
+// MockRevocationService is a mock implementation of the RevocationService interface, only checking tokens.
+type MockRevocationService struct {
+	services.RevocationService
+	mock.Mock
+}
+
+func (m *MockRevocationService) Check(ctx context.Context, claims *services.Claims) error {
+	args := m.Called(claims.ID)
+	return args.Error(0)
+}
+
+func TestAuthenticate_JWT(t *testing.T) {
+	secret := []byte("test-secret-of-at-least-32-bytes")
+	tokens := services.NewTokenService(&config.JWTKeys{
+		SigningMethod: jwt.SigningMethodHS256,
+		SigningKey:    secret,
+		VerifyKeys:    map[string]interface{}{"": secret},
+	}, "go-modules-api", "")
+
+	issue := func(tokenType string) (string, string) {
+		claims := &services.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "7"}, TokenType: tokenType}
+		token, err := tokens.Issue(claims, time.Minute)
+		require.NoError(t, err)
+		return token, claims.ID
+	}
+	access, accessID := issue(services.AccessToken)
+	revoked, revokedID := issue(services.AccessToken)
+	refresh, _ := issue(services.RefreshToken)
+
+	revocations := new(MockRevocationService)
+	revocations.On("Check", accessID).Return(nil)
+	revocations.On("Check", revokedID).Return(exceptions.Unauthorized("Token has been revoked", nil))
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Authenticate(AuthConfig{Enabled: true}, JWTCredential(tokens, revocations)))
+	app.Get("/auth/me", RequireUser(), func(c *fiber.Ctx) error {
+		assert.Equal(t, "7", GetClaims(c).Subject)
+		return ok(c)
+	})
+
+	bearer := func(token string) map[string]string {
+		return map[string]string{fiber.HeaderAuthorization: "Bearer " + token}
+	}
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/auth/me", bearer(access)))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/auth/me", bearer(revoked)))
+	// Refresh tokens are only accepted by the refresh endpoint
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/auth/me", bearer(refresh)))
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/auth/me", bearer("garbage")))
+
+	req := httptest.NewRequest("GET", "/auth/me", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Bearer realm="api"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
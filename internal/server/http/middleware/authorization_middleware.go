@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"strconv"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// policyKey is the fiber.Ctx locals key holding the *requestPolicy of the request
+const policyKey = "policy"
+
+// Authorizer builds the middleware enforcing ModulePermission grants on routes
+type Authorizer struct {
+	service services.AuthorizationService
+	enabled bool
+}
+
+// NewAuthorizer creates an Authorizer. A disabled Authorizer allows every request.
+func NewAuthorizer(service services.AuthorizationService, enabled bool) *Authorizer {
+	return &Authorizer{service: service, enabled: enabled}
+}
+
+// requestPolicy caches the roles of the caller and the decisions taken during a request
+type requestPolicy struct {
+	roleIDs   []uint
+	super     bool
+	decisions map[string]bool
+}
+
+// Require allows the request only when one of the caller's roles is granted the action on the module.
+// It applies to user tokens: API keys are limited by their scopes and unauthenticated requests
+// only reach public routes. The roles of the caller are resolved even when RBAC is disabled, as
+// services bound the scopes of the credentials a user creates with them.
+func (a *Authorizer) Require(module string, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetClaims(c)
+		if claims == nil {
+			return c.Next()
+		}
+
+		policy, err := a.policy(c, claims)
+		if err != nil {
+			return err
+		}
+
+		if a.enabled && !policy.super {
+			decision := module + ":" + action
+			allowed, cached := policy.decisions[decision]
+			if !cached {
+				var hubClientID *uint
+				if id, ok := GetHubClientID(c); ok {
+					hubClientID = &id
+				}
+
+				allowed, err = a.service.Allowed(policy.roleIDs, module, action, hubClientID)
+				if err != nil {
+					return err
+				}
+				policy.decisions[decision] = allowed
+			}
+
+			if !allowed {
+				return exceptions.Forbidden("You do not have permission to perform this action", fiber.Map{"module": module, "action": action})
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireSuper allows the request only when the caller holds a super role, whether RBAC is enabled
+// or not. It guards the operator endpoints controlling the whole process, which no module
+// permission of a hub client may grant.
+func (a *Authorizer) RequireSuper() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetClaims(c)
+		if claims == nil {
+			return exceptions.Unauthorized("This operation requires a user token", nil)
+		}
+
+		super, err := a.Super(c)
+		if err != nil {
+			return err
+		}
+		if !super {
+			return exceptions.Forbidden("This operation requires a super role", nil)
+		}
+		return c.Next()
+	}
+}
+
+// Super reports whether the caller of the request holds a super role, API keys and
+// unauthenticated requests never do
+func (a *Authorizer) Super(c *fiber.Ctx) (bool, error) {
+	claims := GetClaims(c)
+	if claims == nil {
+		return false, nil
+	}
+
+	policy, err := a.policy(c, claims)
+	if err != nil {
+		return false, err
+	}
+	return policy.super, nil
+}
+
+// policy returns the cached policy of the request, resolving the caller's roles on first use
+func (a *Authorizer) policy(c *fiber.Ctx, claims *services.Claims) (*requestPolicy, error) {
+	if policy, ok := c.Locals(policyKey).(*requestPolicy); ok {
+		return policy, nil
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	roleIDs, super, err := a.service.Roles(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &requestPolicy{roleIDs: roleIDs, super: super, decisions: map[string]bool{}}
+	c.Locals(policyKey, policy)
+	ctx := services.WithRoles(c.UserContext(), roleIDs, a.enabled)
+	if super {
+		// Services reserve the changes to super roles to their holders
+		ctx = services.WithSuper(ctx)
+	}
+	c.SetUserContext(ctx)
+	return policy, nil
+}
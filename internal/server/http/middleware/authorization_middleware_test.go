@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// MockAuthorizationService is a mock implementation of the AuthorizationService interface.
+type MockAuthorizationService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorizationService) Roles(userID uint) ([]uint, bool, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]uint), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAuthorizationService) Allowed(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error) {
+	args := m.Called(roleIDs, module, action, hubClientID)
+	return args.Bool(0), args.Error(1)
+}
+
+// authorizerApp serves GET and DELETE /roles behind the Authorizer as the user 7 of the hub client 3
+func authorizerApp(authorizer *Authorizer) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(locals(map[string]interface{}{ClaimsKey: userClaims(), HubClientIDKey: uint(3)}))
+	app.Get("/roles", authorizer.Require("roles", models.ActionRead), authorizer.Require("roles", models.ActionRead), ok)
+	app.Delete("/roles", authorizer.Require("roles", models.ActionDelete), ok)
+	app.Get("/admin/info", authorizer.RequireSuper(), func(c *fiber.Ctx) error {
+		// Services check the super flag of the context
+		if !services.IsSuper(c.UserContext()) {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return ok(c)
+	})
+	return app
+}
+
+func TestAuthorizer_Require(t *testing.T) {
+	service := new(MockAuthorizationService)
+	hubClientID := uint(3)
+	service.On("Roles", uint(7)).Return([]uint{2}, false, nil)
+	service.On("Allowed", []uint{2}, "roles", models.ActionRead, &hubClientID).Return(true, nil).Once()
+	service.On("Allowed", []uint{2}, "roles", models.ActionDelete, &hubClientID).Return(false, nil).Once()
+
+	app := authorizerApp(NewAuthorizer(service, true))
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "DELETE", "/roles", nil))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "GET", "/admin/info", nil))
+
+	// The decision is cached for the rest of the request
+	service.AssertExpectations(t)
+}
+
+func TestAuthorizer_RequireSuper(t *testing.T) {
+	service := new(MockAuthorizationService)
+	service.On("Roles", uint(7)).Return([]uint{1}, true, nil)
+
+	for _, enabled := range []bool{true, false} {
+		app := authorizerApp(NewAuthorizer(service, enabled))
+
+		assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/admin/info", nil))
+		assert.Equal(t, fiber.StatusOK, status(t, app, "DELETE", "/roles", nil))
+	}
+	service.AssertNotCalled(t, "Allowed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorizer_Disabled(t *testing.T) {
+	service := new(MockAuthorizationService)
+	service.On("Roles", uint(7)).Return([]uint{2}, false, nil)
+
+	app := authorizerApp(NewAuthorizer(service, false))
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "DELETE", "/roles", nil))
+	// Disabling RBAC never opens the operator endpoints
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, "GET", "/admin/info", nil))
+}
+
+func TestAuthorizer_RequireSuperWithoutUser(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/admin/info", NewAuthorizer(new(MockAuthorizationService), true).RequireSuper(), ok)
+
+	assert.Equal(t, fiber.StatusUnauthorized, status(t, app, "GET", "/admin/info", nil))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-modules-api/internal/exceptions"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimits holds the maximum request body size of every route
+type BodyLimits struct {
+	// Default applies to the routes without a specific limit
+	Default int
+	routes  []routePattern
+	limits  []int
+}
+
+// ParseBodyLimits parses "METHOD /path=<bytes>" route limits, where the path follows the
+// AuthConfig.PublicRoutes syntax. The first matching route wins.
+func ParseBodyLimits(defaultLimit int, routeLimits []string) (*BodyLimits, error) {
+	limits := &BodyLimits{Default: defaultLimit}
+	for _, entry := range routeLimits {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, value, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid body limit %q, expected <route>=<bytes>", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid body limit %q, the limit must be a positive number of bytes", entry)
+		}
+
+		limits.routes = append(limits.routes, parseRoutePatterns([]string{pattern})...)
+		limits.limits = append(limits.limits, limit)
+	}
+	return limits, nil
+}
+
+// Max returns the largest limit, to be used as the fiber.Config BodyLimit
+func (l *BodyLimits) Max() int {
+	largest := l.Default
+	for _, limit := range l.limits {
+		largest = max(largest, limit)
+	}
+	return largest
+}
+
+// Handler rejects requests whose body exceeds the limit of their route with 413
+func (l *BodyLimits) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := l.Default
+		if i := matchRoute(l.routes, c.Method(), c.Path()); i >= 0 {
+			limit = l.limits[i]
+		}
+
+		if size := max(c.Request().Header.ContentLength(), len(c.Body())); size > limit {
+			return exceptions.NewAPIException(http.StatusRequestEntityTooLarge, "payload_too_large",
+				"Request body is too large", fiber.Map{"limit": limit})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBodyLimits(t *testing.T) {
+	limits, err := ParseBodyLimits(1024, []string{"POST /api/auth/*=16", " ", "PUT /api/roles/:id=4096"})
+	require.NoError(t, err)
+	assert.Equal(t, 1024, limits.Default)
+	assert.Equal(t, 4096, limits.Max())
+
+	for _, invalid := range []string{"POST /api/auth/*", "POST /api/auth/*=0", "POST /api/auth/*=big"} {
+		_, err := ParseBodyLimits(1024, []string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestBodyLimits_Handler(t *testing.T) {
+	limits, err := ParseBodyLimits(32, []string{"POST /api/auth/*=8"})
+	require.NoError(t, err)
+
+	// The server limit stays above the route limits, so the handler answers the oversized requests
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler, BodyLimit: 1024})
+	app.Use(limits.Handler())
+	app.Post("/api/auth/login", ok)
+	app.Post("/api/roles", ok)
+
+	post := func(target string, size int) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest("POST", target, strings.NewReader(strings.Repeat("a", size)))
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, fiber.StatusOK, post("/api/auth/login", 8).StatusCode)
+	assert.Equal(t, fiber.StatusOK, post("/api/roles", 32).StatusCode)
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, post("/api/roles", 33).StatusCode)
+
+	// The route limit is tighter than the default one
+	resp := post("/api/auth/login", 9)
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	var problem map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "payload_too_large", problem["code"])
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// DeprecationConfig describes a deprecated API version or route.
+type DeprecationConfig struct {
+	// Version is the API version the route belongs to, used for logging.
+	Version string
+	// DeprecatedAt is when the route was deprecated. When zero, the Deprecation header is sent as "true".
+	DeprecatedAt time.Time
+	// Sunset is when the route will stop responding. No Sunset header is sent when zero.
+	Sunset time.Time
+	// Link points to the successor version or migration guide.
+	Link string
+}
+
+// DeprecatedRoute deprecates the routes matching a "METHOD /path" pattern, following the
+// AuthConfig.PublicRoutes syntax.
+type DeprecatedRoute struct {
+	Route string
+	DeprecationConfig
+}
+
+// Deprecation adds the Deprecation, Sunset and Link headers to the responses of the deprecated
+// routes and logs every call made to them. The first matching route wins, other requests are
+// left untouched.
+func Deprecation(log *zap.Logger, routes []DeprecatedRoute) fiber.Handler {
+	log = log.Named("deprecation")
+
+	patterns := make([]routePattern, 0, len(routes))
+	deprecations := make([]func(c *fiber.Ctx), 0, len(routes))
+	for _, route := range routes {
+		for _, pattern := range parseRoutePatterns([]string{route.Route}) {
+			patterns = append(patterns, pattern)
+			deprecations = append(deprecations, deprecate(log, route.DeprecationConfig))
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if i := matchRoute(patterns, c.Method(), c.Path()); i >= 0 {
+			deprecations[i](c)
+		}
+		return c.Next()
+	}
+}
+
+// deprecate returns a function setting the deprecation headers of cfg and logging the call
+func deprecate(log *zap.Logger, cfg DeprecationConfig) func(c *fiber.Ctx) {
+	deprecation := "true"
+	if !cfg.DeprecatedAt.IsZero() {
+		deprecation = "@" + strconv.FormatInt(cfg.DeprecatedAt.Unix(), 10)
+	}
+
+	return func(c *fiber.Ctx) {
+		c.Set("Deprecation", deprecation)
+		if !cfg.Sunset.IsZero() {
+			c.Set("Sunset", cfg.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if cfg.Link != "" {
+			c.Append("Link", "<"+cfg.Link+`>; rel="deprecation"`)
+		}
+
+		utils.ContextLogger(c.UserContext(), log).Warn("Deprecated API call",
+			zap.String("version", cfg.Version),
+			zap.String("method", c.Method()),
+			zap.String("path", c.Path()),
+			zap.String("ip", c.IP()),
+		)
+	}
+}
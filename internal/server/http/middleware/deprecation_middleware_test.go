@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDeprecation(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Deprecation(zap.New(core), []DeprecatedRoute{
+		{Route: "GET /api/v1/roles/:id", DeprecationConfig: DeprecationConfig{
+			Version:      "v1",
+			DeprecatedAt: time.Unix(1767225600, 0),
+			Sunset:       sunset,
+			Link:         "/api/v2/roles/{id}",
+		}},
+		{Route: "/api/v0/*", DeprecationConfig: DeprecationConfig{Version: "v0"}},
+	}))
+	app.Get("/api/v1/roles", ok)
+	app.Get("/api/v1/roles/:id", ok)
+	app.Delete("/api/v1/roles/:id", ok)
+	app.Get("/api/v0/roles", ok)
+
+	req := httptest.NewRequest("GET", "/api/v1/roles/3", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "@1767225600", resp.Header.Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", resp.Header.Get("Sunset"))
+	assert.Equal(t, `</api/v2/roles/{id}>; rel="deprecation"`, resp.Header.Get("Link"))
+
+	req = httptest.NewRequest("GET", "/api/v0/roles", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Empty(t, resp.Header.Get("Sunset"))
+
+	// Other routes, and other methods of the deprecated route, are left untouched
+	for _, target := range []struct{ method, path string }{{"GET", "/api/v1/roles"}, {"DELETE", "/api/v1/roles/3"}} {
+		req = httptest.NewRequest(target.method, target.path, nil)
+		resp, err = app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Header.Get("Deprecation"), target.path)
+	}
+
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "v1", logs.All()[0].ContextMap()["version"])
+	assert.Equal(t, "v0", logs.All()[1].ContextMap()["version"])
+}
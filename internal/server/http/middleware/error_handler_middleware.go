@@ -2,10 +2,14 @@ package middleware
 
 import (
 	"errors"
+	"net/http"
+	"strings"
 
 	"go-modules-api/internal/exceptions"
+	"go-modules-api/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
 func ErrorHandler(c *fiber.Ctx, err error) error {
@@ -18,5 +22,18 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 		return apiErr.Response(c)
 	}
 
-	return exceptions.InternalServerError("An unexpected error occurred", err.Error()).Response(c)
+	// Errors raised by fiber itself, such as unknown routes or oversized bodies
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code := strings.ReplaceAll(strings.ToLower(http.StatusText(fiberErr.Code)), " ", "_")
+		return exceptions.NewAPIException(fiberErr.Code, code, fiberErr.Message, nil).Response(c)
+	}
+
+	// Unexpected errors may describe internals, they are logged with the request ID instead of returned
+	utils.LoggerFromContext(c.UserContext()).Error("Unexpected error",
+		zap.String("method", c.Method()),
+		zap.String("path", c.Path()),
+		zap.Error(err),
+	)
+	return exceptions.InternalServerError("An unexpected error occurred", nil).Response(c)
 }
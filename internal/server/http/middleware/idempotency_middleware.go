@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client generated key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a stored key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with the body of an idempotent response
+var replayedHeaders = []string{
+	fiber.HeaderLocation, fiber.HeaderETag, fiber.HeaderLastModified,
+	"Deprecation", "Sunset", fiber.HeaderLink,
+}
+
+// Idempotency makes authenticated POST requests sent with an Idempotency-Key header safe to retry.
+// Keys belong to the caller that sent them. The first response is stored and replayed byte for
+// byte, with its Location, ETag and deprecation headers, on retries of the same caller with
+// the same payload.
+// Server errors release the key so the request can be retried. Responses sent with
+// Cache-Control: no-store, such as those carrying credentials, are never stored and release it too.
+func Idempotency(service services.IdempotencyService, log *zap.Logger) fiber.Handler {
+	log = log.Named("idempotency")
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+		owner, ok := idempotencyOwner(c)
+		if !ok {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return exceptions.BadRequest("Idempotency-Key is too long", fiber.Map{"field": IdempotencyKeyHeader, "max": maxIdempotencyKeyLength})
+		}
+
+		path := c.OriginalURL()
+		record, replay, err := service.Begin(owner, key, c.Method(), path, requestHash(c.Method(), path, c.Body()))
+		if err != nil {
+			return err
+		}
+
+		if replay {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, record.ContentType)
+			for name, value := range record.ResponseHeaders {
+				c.Set(name, value)
+			}
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+
+		// Render errors here so the final response can be stored
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		log := utils.ContextLogger(c.UserContext(), log)
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError || noStore(c) {
+			if err := service.Release(record); err != nil {
+				log.Error("Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+			}
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		contentType := string(c.Response().Header.ContentType())
+		if err := service.Complete(record, status, contentType, responseHeaders(c), body); err != nil {
+			log.Error("Failed to store idempotent response", zap.String("key", key), zap.Error(err))
+			if err := service.Release(record); err != nil {
+				log.Error("Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+			}
+		}
+
+		return nil
+	}
+}
+
+// idempotencyOwner returns the caller of an authenticated request. Anonymous requests have no
+// owner their keys could be scoped to.
+func idempotencyOwner(c *fiber.Ctx) (services.IdempotencyOwner, bool) {
+	principal := Principal(c)
+	hubClientID, _ := GetHubClientID(c)
+	return services.IdempotencyOwner{Principal: principal, HubClientID: hubClientID}, principal != ""
+}
+
+// responseHeaders returns the replayed headers the response carries
+func responseHeaders(c *fiber.Ctx) map[string]string {
+	headers := map[string]string{}
+	for _, name := range replayedHeaders {
+		if value := c.Response().Header.Peek(name); len(value) > 0 {
+			headers[name] = string(value)
+		}
+	}
+	return headers
+}
+
+// noStore reports whether the response must not be stored
+func noStore(c *fiber.Ctx) bool {
+	return strings.Contains(string(c.Response().Header.Peek(fiber.HeaderCacheControl)), "no-store")
+}
+
+// requestHash fingerprints a request so a reused key with a different payload can be detected
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + "\n" + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
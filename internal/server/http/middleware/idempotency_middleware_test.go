@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// MockIdempotencyService is a mock implementation of the IdempotencyService interface.
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Begin(owner services.IdempotencyOwner, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error) {
+	args := m.Called(owner, key, method, path, requestHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.IdempotencyKey), args.Bool(1), args.Error(2)
+	}
+	return nil, args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyService) Complete(record *models.IdempotencyKey, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	args := m.Called(record, statusCode, contentType, headers, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) Release(record *models.IdempotencyKey) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// idempotencyApp serves POST /api/roles behind Idempotency, as the user 7 of the hub client 3 unless anonymous
+func idempotencyApp(service services.IdempotencyService, anonymous bool, handler fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		if !anonymous {
+			c.Locals(ClaimsKey, &services.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "7"}})
+			c.Locals(HubClientIDKey, uint(3))
+		}
+		return c.Next()
+	})
+	app.Use(Idempotency(service, zap.NewNop()))
+	app.Post("/api/roles", handler)
+	return app
+}
+
+func TestIdempotency_StoresResponseOfCaller(t *testing.T) {
+	service := new(MockIdempotencyService)
+	record := &models.IdempotencyKey{Key: "abc"}
+	owner := services.IdempotencyOwner{Principal: "user:7", HubClientID: 3}
+	service.On("Begin", owner, "abc", "POST", "/api/roles", mock.Anything).Return(record, false, nil)
+	headers := map[string]string{fiber.HeaderLocation: "/api/roles/1"}
+	service.On("Complete", record, fiber.StatusCreated, fiber.MIMEApplicationJSON, headers, []byte(`{"id":1}`)).Return(nil)
+
+	app := idempotencyApp(service, false, func(c *fiber.Ctx) error {
+		c.Location("/api/roles/1")
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+	})
+
+	req := httptest.NewRequest("POST", "/api/roles", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	service.AssertExpectations(t)
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	service := new(MockIdempotencyService)
+	record := &models.IdempotencyKey{
+		Key: "abc", Completed: true, StatusCode: fiber.StatusCreated, ContentType: fiber.MIMEApplicationJSON,
+		ResponseHeaders: map[string]string{fiber.HeaderLocation: "/api/roles/1", fiber.HeaderETag: `W/"1"`},
+		ResponseBody:    []byte(`{"id":1}`),
+	}
+	service.On("Begin", mock.Anything, "abc", "POST", "/api/roles", mock.Anything).Return(record, true, nil)
+
+	app := idempotencyApp(service, false, func(c *fiber.Ctx) error {
+		t.Fatal("replayed requests must not reach the handler")
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/api/roles", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, "/api/roles/1", resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, `W/"1"`, resp.Header.Get(fiber.HeaderETag))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":1}`, string(body))
+}
+
+func TestIdempotency_DoesNotStoreCredentials(t *testing.T) {
+	service := new(MockIdempotencyService)
+	record := &models.IdempotencyKey{Key: "abc"}
+	service.On("Begin", mock.Anything, "abc", "POST", "/api/roles", mock.Anything).Return(record, false, nil)
+	service.On("Release", record).Return(nil)
+
+	app := idempotencyApp(service, false, func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"key": "secret"})
+	})
+
+	req := httptest.NewRequest("POST", "/api/roles", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	service.AssertExpectations(t)
+	service.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotency_IgnoresAnonymousRequests(t *testing.T) {
+	service := new(MockIdempotencyService)
+
+	app := idempotencyApp(service, true, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/api/roles", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	service.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotency_RejectsLongKeys(t *testing.T) {
+	service := new(MockIdempotencyService)
+
+	app := idempotencyApp(service, false, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/api/roles", nil)
+	req.Header.Set(IdempotencyKeyHeader, strings.Repeat("a", maxIdempotencyKeyLength+1))
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
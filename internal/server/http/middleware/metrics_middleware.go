@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net"
+	"strconv"
+	"time"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records the count, latency and in-flight number of requests by route template.
+// Requests no route matched are labelled with the prefix of the last middleware they went through,
+// so the route label stays bounded. It must come right before Recover, so panics recovered into
+// a 500 are recorded too, and errors are rendered by the error handler before their status is recorded.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route := c.Route().Path
+		status := strconv.Itoa(c.Response().StatusCode())
+
+		metrics.HTTPRequests.WithLabelValues(c.Method(), route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Method(), route, status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// MetricsAccess restricts the metrics endpoint to the allowed networks, when any, and to requests
+// with the bearer token, when set
+func MetricsAccess(token string, allowedNetworks []*net.IPNet) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(allowedNetworks) > 0 && !ipAllowed(c.IP(), allowedNetworks) {
+			return exceptions.Forbidden("Metrics are not available from this address", nil)
+		}
+
+		if token != "" {
+			provided, ok := bearerToken(c)
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="metrics"`)
+				return exceptions.Unauthorized("Invalid metrics token", nil)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// ParseNetworks parses CIDR blocks, accepting bare IPs as single address blocks
+func ParseNetworks(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if ip := net.ParseIP(value); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func ipAllowed(value string, networks []*net.IPNet) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
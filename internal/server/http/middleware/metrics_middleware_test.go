@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-modules-api/internal/metrics"
+)
+
+func TestMetrics_CountsRecoveredPanics(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Metrics())
+	app.Use(Recover(zap.NewNop()))
+	app.Get("/metrics-panic", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	counter := metrics.HTTPRequests.WithLabelValues("GET", "/metrics-panic", "500")
+	before := testutil.ToFloat64(counter)
+
+	assert.Equal(t, fiber.StatusInternalServerError, status(t, app, "GET", "/metrics-panic", nil))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestMetricsAccess(t *testing.T) {
+	// app.Test connections come from 0.0.0.0
+	local, err := ParseNetworks([]string{"0.0.0.0"})
+	require.NoError(t, err)
+	remote, err := ParseNetworks([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	bearer := func(token string) map[string]string {
+		return map[string]string{fiber.HeaderAuthorization: "Bearer " + token}
+	}
+
+	tests := []struct {
+		name    string
+		handler fiber.Handler
+		headers map[string]string
+		want    int
+	}{
+		{name: "open", handler: MetricsAccess("", nil), want: fiber.StatusOK},
+		{name: "allowed network", handler: MetricsAccess("", local), want: fiber.StatusOK},
+		{name: "other network", handler: MetricsAccess("", remote), want: fiber.StatusForbidden},
+		{name: "token", handler: MetricsAccess("scrape", nil), headers: bearer("scrape"), want: fiber.StatusOK},
+		{name: "wrong token", handler: MetricsAccess("scrape", nil), headers: bearer("guess"), want: fiber.StatusUnauthorized},
+		{name: "missing token", handler: MetricsAccess("scrape", nil), want: fiber.StatusUnauthorized},
+		{name: "token from other network", handler: MetricsAccess("scrape", remote), headers: bearer("scrape"), want: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/metrics", tt.handler, ok)
+
+			assert.Equal(t, tt.want, status(t, app, "GET", "/metrics", tt.headers))
+		})
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.1", "192.168.0.0/16", "::1"})
+	require.NoError(t, err)
+	require.Len(t, networks, 3)
+	assert.Equal(t, "10.0.0.1/32", networks[0].String())
+	assert.Equal(t, "::1/128", networks[2].String())
+
+	_, err = ParseNetworks([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/ratelimit"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// maxClientIDLength bounds the client_id used as a rate limit key
+const maxClientIDLength = 128
+
+// takenBucketsKey is the fiber.Ctx locals key holding the buckets earlier limits of the request took a token from
+const takenBucketsKey = "rate_limit_taken"
+
+// RateLimiter builds the middleware enforcing the rate limits of each route group
+type RateLimiter struct {
+	store    ratelimit.Store
+	policies ratelimit.Policies
+	enabled  bool
+	log      *zap.Logger
+}
+
+// NewRateLimiter creates a RateLimiter. A disabled RateLimiter allows every request.
+func NewRateLimiter(store ratelimit.Store, policies ratelimit.Policies, enabled bool, log *zap.Logger) *RateLimiter {
+	return &RateLimiter{store: store, policies: policies, enabled: enabled, log: log.Named("rate_limit")}
+}
+
+// principal identifies a caller a rate applies to
+type principal struct {
+	kind string
+	id   string
+}
+
+// Limit takes a token from the bucket of every principal of the request that the group limits:
+// its API key, its hub client, its IP and the OAuth2 client_id it authenticates with, or only the
+// given kinds of principals. Tokens are only
+// taken when every bucket has one, otherwise the request is rejected with 429 and the tokens
+// earlier limits of the request took, such as the IP one taken before authentication, are
+// refunded. The RateLimit headers describe the most restrictive bucket.
+// Store errors are logged and let the request through.
+func (l *RateLimiter) Limit(group string, kinds ...string) fiber.Handler {
+	rates := l.policies[group]
+	if !l.enabled || len(rates) == 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		var buckets []ratelimit.Bucket
+		for _, p := range principals(c, rates) {
+			rate, ok := rates[p.kind]
+			if !ok || len(kinds) > 0 && !slices.Contains(kinds, p.kind) {
+				continue
+			}
+			buckets = append(buckets, ratelimit.Bucket{Key: group + ":" + p.kind + ":" + p.id, Rate: rate})
+		}
+		if len(buckets) == 0 {
+			return c.Next()
+		}
+
+		results, err := l.store.Take(buckets, time.Now())
+		if err != nil {
+			utils.ContextLogger(c.UserContext(), l.log).Error("Failed to take a rate limit token", zap.String("group", group), zap.Error(err))
+			return c.Next()
+		}
+
+		limited := 0
+		for i := range results {
+			if moreRestrictive(results[i], results[limited]) {
+				limited = i
+			}
+		}
+		result, rate := results[limited], buckets[limited].Rate
+
+		c.Set("RateLimit-Limit", strconv.Itoa(rate.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", seconds(result.Reset))
+		c.Set("RateLimit-Policy", strconv.Itoa(rate.Limit)+";w="+seconds(rate.Period))
+
+		taken, _ := c.Locals(takenBucketsKey).([]ratelimit.Bucket)
+		if !result.Allowed {
+			if len(taken) > 0 {
+				if err := l.store.Refund(taken, time.Now()); err != nil {
+					utils.ContextLogger(c.UserContext(), l.log).Error("Failed to refund rate limit tokens", zap.String("group", group), zap.Error(err))
+				}
+				c.Locals(takenBucketsKey, nil)
+			}
+
+			c.Set(fiber.HeaderRetryAfter, seconds(result.RetryAfter))
+			return exceptions.TooManyRequests("Too many requests, retry later", fiber.Map{"retry_after": seconds(result.RetryAfter)})
+		}
+
+		c.Locals(takenBucketsKey, append(taken, buckets...))
+		return c.Next()
+	}
+}
+
+// principals returns the API key, hub client, IP and OAuth2 client_id of the request, when known.
+// The client_id is only looked up when the rates limit it, since it may require parsing the body.
+func principals(c *fiber.Ctx, rates map[string]ratelimit.Rate) []principal {
+	result := make([]principal, 0, 4)
+	if key := GetAPIKey(c); key != nil {
+		result = append(result, principal{kind: ratelimit.PrincipalAPIKey, id: strconv.FormatUint(uint64(key.ID), 10)})
+	}
+	if hubClientID, ok := GetHubClientID(c); ok {
+		result = append(result, principal{kind: ratelimit.PrincipalHubClient, id: strconv.FormatUint(uint64(hubClientID), 10)})
+	}
+	if _, ok := rates[ratelimit.PrincipalClientID]; ok {
+		if clientID := oauthClientID(c); clientID != "" {
+			result = append(result, principal{kind: ratelimit.PrincipalClientID, id: clientID})
+		}
+	}
+	return append(result, principal{kind: ratelimit.PrincipalIP, id: c.IP()})
+}
+
+// oauthClientID returns the client_id of the Basic authorization header or of the body.
+// Long IDs are truncated to bound the size of the keys, sharing a bucket with their prefix.
+func oauthClientID(c *fiber.Ctx) string {
+	clientID, _, ok, err := BasicCredentials(c)
+	if !ok {
+		var body struct {
+			ClientID string `json:"client_id" form:"client_id"`
+		}
+		if c.BodyParser(&body) == nil {
+			clientID = strings.Clone(body.ClientID)
+		}
+	} else if err != nil {
+		return ""
+	}
+	if len(clientID) > maxClientIDLength {
+		return clientID[:maxClientIDLength]
+	}
+	return clientID
+}
+
+// moreRestrictive reports whether a must wait longer than b for a token, or leaves fewer tokens
+func moreRestrictive(a ratelimit.Result, b ratelimit.Result) bool {
+	if a.RetryAfter != b.RetryAfter {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// seconds formats a duration as whole seconds, rounded up
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/ratelimit"
+)
+
+// failingStore is a ratelimit.Store that is always unavailable
+type failingStore struct{}
+
+func (failingStore) Take([]ratelimit.Bucket, time.Time) ([]ratelimit.Result, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func (failingStore) Refund([]ratelimit.Bucket, time.Time) error {
+	return errors.New("store unavailable")
+}
+
+func rateLimitPolicies(t *testing.T, values map[string]string) ratelimit.Policies {
+	policies, err := ratelimit.ParsePolicies(values)
+	require.NoError(t, err)
+	return policies
+}
+
+func TestLimit_RejectsWhenABucketIsEmpty(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{
+		"default.ip":      "2/1m",
+		"default.api_key": "1/1m",
+	}), true, zap.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) != "" {
+			c.Locals(APIKeyKey, &models.APIKey{BaseID: models.BaseID{ID: 1}})
+		}
+		return c.Next()
+	})
+	app.Use(limiter.Limit("default"))
+	app.Get("/roles", ok)
+
+	apiKey := map[string]string{fiber.HeaderAuthorization: "Bearer gma_key"}
+
+	req := httptest.NewRequest("GET", "/roles", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer gma_key")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "1;w=60", resp.Header.Get("RateLimit-Policy"))
+
+	req = httptest.NewRequest("GET", "/roles", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer gma_key")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "GET", "/roles", apiKey))
+	}
+
+	// The requests denied by the API key bucket left the IP bucket untouched
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+	assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "GET", "/roles", nil))
+}
+
+func TestLimit_RefundsEarlierLimits(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{
+		"default.ip":      "2/1m",
+		"default.api_key": "1/1m",
+	}), true, zap.NewNop())
+
+	// The IP is limited before authentication and the API key after it, as in the routes
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(limiter.Limit("default", ratelimit.PrincipalIP))
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) != "" {
+			c.Locals(APIKeyKey, &models.APIKey{BaseID: models.BaseID{ID: 1}})
+		}
+		return c.Next()
+	})
+	app.Use(limiter.Limit("default", ratelimit.PrincipalAPIKey))
+	app.Get("/roles", ok)
+
+	apiKey := map[string]string{fiber.HeaderAuthorization: "Bearer gma_key"}
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", apiKey))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "GET", "/roles", apiKey))
+	}
+
+	// The requests denied by the API key bucket got their IP token back
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+	assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "GET", "/roles", nil))
+}
+
+func TestLimit_OnlyGivenPrincipals(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{
+		"default.ip":         "1/1m",
+		"default.hub_client": "5/1m",
+	}), true, zap.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(limiter.Limit("default", ratelimit.PrincipalHubClient))
+	app.Get("/roles", ok)
+
+	// Without a hub client no bucket applies
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+	}
+}
+
+func TestLimit_Disabled(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{"default.ip": "1/1m"}), false, zap.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(limiter.Limit("default"))
+	app.Get("/roles", ok)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+	}
+}
+
+func TestLimit_StoreErrorsLetRequestsThrough(t *testing.T) {
+	limiter := NewRateLimiter(failingStore{}, rateLimitPolicies(t, map[string]string{"default.ip": "1/1m"}), true, zap.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(limiter.Limit("default"))
+	app.Get("/roles", ok)
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+}
+
+func TestLimit_TrustedProxy(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{"default.ip": "1/1m"}), true, zap.NewNop())
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler:            ErrorHandler,
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0/0"},
+		EnableIPValidation:      true,
+	})
+	app.Use(limiter.Limit("default"))
+	app.Get("/roles", ok)
+
+	// Clients behind the same proxy have their own bucket
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", map[string]string{"X-Real-IP": "203.0.113.1"}))
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", map[string]string{"X-Real-IP": "203.0.113.2"}))
+	assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "GET", "/roles", map[string]string{"X-Real-IP": "203.0.113.1"}))
+}
+
+func TestLimit_OAuthClientID(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitPolicies(t, map[string]string{
+		"auth.client_id": "1/1m",
+	}), true, zap.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(limiter.Limit("auth"))
+	app.Post("/oauth/token", ok)
+
+	basic := func(clientID string) map[string]string {
+		return map[string]string{fiber.HeaderAuthorization: "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":wrong"))}
+	}
+	form := func(clientID string) int {
+		req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=client_credentials&client_id="+clientID))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "POST", "/oauth/token", basic("partner")))
+	// Guessing the secret of the same client from the body or another IP is limited too
+	assert.Equal(t, fiber.StatusTooManyRequests, status(t, app, "POST", "/oauth/token", basic("partner")))
+	assert.Equal(t, fiber.StatusTooManyRequests, form("partner"))
+	assert.Equal(t, fiber.StatusOK, form("other"))
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/metrics"
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// PanicReport describes a panic recovered while handling a request
+type PanicReport struct {
+	Value     interface{}
+	Stack     []byte
+	Method    string
+	Path      string
+	Route     string
+	RequestID string
+}
+
+// PanicReporter forwards recovered panics, e.g. to an error tracker
+type PanicReporter interface {
+	ReportPanic(ctx context.Context, report PanicReport)
+}
+
+// Recover turns a panic of a later handler into a 500 response that does not expose the panic,
+// after logging it with its stack, counting it and handing it to every reporter
+func Recover(log *zap.Logger, reporters ...PanicReporter) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+
+			report := PanicReport{
+				Value:     value,
+				Stack:     debug.Stack(),
+				Method:    c.Method(),
+				Path:      c.Path(),
+				Route:     c.Route().Path,
+				RequestID: GetRequestID(c),
+			}
+			ctx := c.UserContext()
+
+			utils.ContextLogger(ctx, log).Error("Recovered from panic",
+				zap.String("panic", fmt.Sprint(value)),
+				zap.String("method", report.Method),
+				zap.String("path", report.Path),
+				zap.String("route", report.Route),
+				zap.ByteString("stack", report.Stack),
+			)
+			metrics.HTTPPanics.WithLabelValues(report.Method, report.Route).Inc()
+
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(fmt.Errorf("panic: %v", value))
+			span.SetStatus(codes.Error, "panic")
+
+			for _, reporter := range reporters {
+				reportPanic(ctx, log, reporter, report)
+			}
+
+			err = exceptions.InternalServerError("An unexpected error occurred", nil)
+		}()
+
+		return c.Next()
+	}
+}
+
+// reportPanic hands the report to the reporter, so a failing reporter cannot crash the process
+func reportPanic(ctx context.Context, log *zap.Logger, reporter PanicReporter, report PanicReport) {
+	defer func() {
+		if value := recover(); value != nil {
+			utils.ContextLogger(ctx, log).Error("Panic reporter failed", zap.String("panic", fmt.Sprint(value)))
+		}
+	}()
+	reporter.ReportPanic(ctx, report)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-modules-api/internal/metrics"
+)
+
+// reporterFunc adapts a function to the PanicReporter interface
+type reporterFunc func(ctx context.Context, report PanicReport)
+
+func (f reporterFunc) ReportPanic(ctx context.Context, report PanicReport) {
+	f(ctx, report)
+}
+
+func TestRecover(t *testing.T) {
+	var reports []PanicReport
+	failing := reporterFunc(func(context.Context, PanicReport) { panic("reporter down") })
+	recording := reporterFunc(func(_ context.Context, report PanicReport) { reports = append(reports, report) })
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Recover(zap.NewNop(), failing, recording))
+	app.Use(RequestID())
+	app.Get("/boom", func(c *fiber.Ctx) error {
+		panic("secret internal state")
+	})
+
+	panics := testutil.ToFloat64(metrics.HTTPPanics.WithLabelValues("GET", "/boom"))
+
+	req := httptest.NewRequest("GET", "/boom", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "secret internal state")
+
+	var problem map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &problem))
+	assert.Equal(t, "req-1", problem["request_id"])
+
+	assert.Equal(t, panics+1, testutil.ToFloat64(metrics.HTTPPanics.WithLabelValues("GET", "/boom")))
+
+	// The failing reporter did not keep the others from reporting
+	require.Len(t, reports, 1)
+	assert.Equal(t, "secret internal state", reports[0].Value)
+	assert.Equal(t, "/boom", reports[0].Route)
+	assert.Equal(t, "req-1", reports[0].RequestID)
+	assert.NotEmpty(t, reports[0].Stack)
+}
+
+func TestRecover_PassesThrough(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Recover(zap.NewNop()))
+	app.Get("/roles", ok)
+
+	assert.Equal(t, fiber.StatusOK, status(t, app, "GET", "/roles", nil))
+}
+
+func TestErrorHandler_HidesUnexpectedErrors(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestID())
+	app.Get("/roles", func(c *fiber.Ctx) error {
+		return errors.New("pq: password authentication failed for user postgres")
+	})
+
+	req := httptest.NewRequest("GET", "/roles", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "postgres")
+	assert.Contains(t, string(body), resp.Header.Get(fiber.HeaderXRequestID))
+}
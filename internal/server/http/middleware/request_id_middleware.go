@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"strings"
+
+	"go-modules-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDKey is the fiber.Ctx Locals key holding the ID of the request
+	RequestIDKey = "request_id"
+
+	// CorrelationIDHeader is accepted in place of X-Request-ID from callers using that name
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID identifies every request by the X-Request-ID, or X-Correlation-ID, header of the caller
+// when it is safe to log, or by a generated UUID. The ID is echoed in the X-Request-ID response
+// header and added to every log entry of the request through its user context.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" {
+			id = c.Get(CorrelationIDHeader)
+		}
+		if validRequestID(id) {
+			// Header values are only valid until the handler returns, the ID outlives it in log fields
+			id = strings.Clone(id)
+		} else {
+			id = uuid.NewString()
+		}
+
+		c.Set(fiber.HeaderXRequestID, id)
+		c.Locals(RequestIDKey, id)
+
+		ctx := c.UserContext()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+		c.SetUserContext(utils.WithLogFields(ctx, zap.String("request_id", id)))
+
+		return c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request
+func GetRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(RequestIDKey).(string)
+	return id
+}
+
+// validRequestID accepts printable ASCII IDs without spaces, so caller IDs cannot forge log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/exceptions"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		want      string
+		generated bool
+	}{
+		{name: "accepted", headers: map[string]string{fiber.HeaderXRequestID: "req-1"}, want: "req-1"},
+		{name: "correlation id", headers: map[string]string{CorrelationIDHeader: "corr-1"}, want: "corr-1"},
+		{name: "request id first", headers: map[string]string{fiber.HeaderXRequestID: "req-1", CorrelationIDHeader: "corr-1"}, want: "req-1"},
+		{name: "generated", headers: nil, generated: true},
+		{name: "spaces rejected", headers: map[string]string{fiber.HeaderXRequestID: "forged log line"}, generated: true},
+		{name: "too long rejected", headers: map[string]string{fiber.HeaderXRequestID: strings.Repeat("a", maxRequestIDLength+1)}, generated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(RequestID())
+			app.Get("/roles", func(c *fiber.Ctx) error {
+				return c.SendString(GetRequestID(c))
+			})
+
+			req := httptest.NewRequest("GET", "/roles", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+
+			id := resp.Header.Get(fiber.HeaderXRequestID)
+			if tt.generated {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.want, id)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, id, string(body))
+		})
+	}
+}
+
+func TestRequestID_InProblems(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestID())
+	app.Get("/roles/:id", func(c *fiber.Ctx) error {
+		return exceptions.NotFound("Role not found", nil)
+	})
+
+	req := httptest.NewRequest("GET", "/roles/3", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	var problem map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "req-1", problem["request_id"])
+	assert.Equal(t, "/roles/3", problem["instance"])
+}
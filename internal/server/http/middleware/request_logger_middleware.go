@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"go-modules-api/utils"
+
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
@@ -12,7 +14,7 @@ func RequestLogger(log *zap.Logger) fiber.Handler {
 		start := time.Now()
 		err := c.Next()
 		duration := time.Since(start)
-		log.Info("Request",
+		utils.ContextLogger(c.UserContext(), log).Info("Request",
 			zap.String("method", c.Method()),
 			zap.String("path", c.Path()),
 			zap.Int("status", c.Response().StatusCode()),
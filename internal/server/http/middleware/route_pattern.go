@@ -0,0 +1,90 @@
+package middleware
+
+import "strings"
+
+// routePattern is a parsed "METHOD /path" pattern. The method may be omitted,
+// a :name segment matches any single path segment and a trailing * matches any path suffix.
+type routePattern struct {
+	method string
+	path   string
+	prefix bool
+	params bool
+}
+
+func parseRoutePatterns(patterns []string) []routePattern {
+	routes := make([]routePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		route := routePattern{path: pattern}
+		if method, path, found := strings.Cut(pattern, " "); found {
+			route.method = strings.ToUpper(method)
+			route.path = strings.TrimSpace(path)
+		}
+		if strings.HasSuffix(route.path, "*") {
+			route.prefix = true
+			route.path = strings.TrimSuffix(route.path, "*")
+		}
+		route.params = strings.Contains(route.path, "/:")
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// matchRoute returns the index of the first pattern matching the request, or -1
+func matchRoute(routes []routePattern, method string, path string) int {
+	for i, route := range routes {
+		if route.method != "" && route.method != method {
+			continue
+		}
+		if route.params {
+			if matchSegments(route, path) {
+				return i
+			}
+			continue
+		}
+		if route.prefix && strings.HasPrefix(path, route.path) {
+			return i
+		}
+		if strings.TrimSuffix(path, "/") == strings.TrimSuffix(route.path, "/") {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchSegments matches a path against a pattern holding :name segments
+func matchSegments(route routePattern, path string) bool {
+	patternSegments := strings.Split(route.path, "/")
+	pathSegments := strings.Split(path, "/")
+	if !route.prefix {
+		patternSegments = strings.Split(strings.TrimSuffix(route.path, "/"), "/")
+		pathSegments = strings.Split(strings.TrimSuffix(path, "/"), "/")
+		if len(pathSegments) != len(patternSegments) {
+			return false
+		}
+	} else if len(pathSegments) < len(patternSegments) {
+		return false
+	}
+
+	last := len(patternSegments) - 1
+	for i, segment := range patternSegments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			if pathSegments[i] == "" {
+				return false
+			}
+		case route.prefix && i == last:
+			// The last segment of a prefix may be partial, like "/roles*" matching "/roles_archive"
+			if !strings.HasPrefix(pathSegments[i], segment) {
+				return false
+			}
+		case pathSegments[i] != segment:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRoute(t *testing.T) {
+	tests := []struct {
+		pattern string
+		method  string
+		path    string
+		want    bool
+	}{
+		{pattern: "POST /api/auth/login", method: "POST", path: "/api/auth/login", want: true},
+		{pattern: "POST /api/auth/login", method: "POST", path: "/api/auth/login/", want: true},
+		{pattern: "POST /api/auth/login", method: "GET", path: "/api/auth/login", want: false},
+		{pattern: "/api/auth/*", method: "GET", path: "/api/auth/refresh", want: true},
+		{pattern: "/api/auth/*", method: "GET", path: "/api/authz", want: false},
+		{pattern: "GET /api/roles/:id", method: "GET", path: "/api/roles/3", want: true},
+		{pattern: "GET /api/roles/:id", method: "GET", path: "/api/roles/3/", want: true},
+		{pattern: "GET /api/roles/:id", method: "GET", path: "/api/roles", want: false},
+		{pattern: "GET /api/roles/:id", method: "GET", path: "/api/roles//", want: false},
+		{pattern: "GET /api/roles/:id", method: "GET", path: "/api/roles/3/users", want: false},
+		{pattern: "/api/hub_clients/:id/api_keys*", method: "POST", path: "/api/hub_clients/3/api_keys/4/rotate", want: true},
+		{pattern: "/api/hub_clients/:id/api_keys*", method: "POST", path: "/api/hub_clients/3/certificates", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			got := matchRoute(parseRoutePatterns([]string{tt.pattern}), tt.method, tt.path) >= 0
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
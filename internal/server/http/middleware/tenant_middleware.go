@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/services"
+	"go-modules-api/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HubClientHeader selects the hub client a request acts for, by ID or ExternalID
+const HubClientHeader = "X-Hub-Client"
+
+// Tenant resolves the hub client of the request and carries it in the user context, where the
+// tenant GORM callbacks scope every tenant-owned query to it, and in the HubClientIDKey local.
+// Callers bound to a hub client, by their API key or the hub_client_id claim of their token,
+// can only act for it; other users, and users holding a super role, select one with the
+// X-Hub-Client header.
+func Tenant(service services.TenantService, authorizer *Authorizer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		hubClientID, ok := GetHubClientID(c)
+		isBound := ok
+		if !ok {
+			if claims := GetClaims(c); claims != nil && claims.HubClientID != nil {
+				// Every issued token carries the hub client of its user, holders of a super role act for any
+				super, err := authorizer.Super(c)
+				if err != nil {
+					return err
+				}
+				hubClientID, ok, isBound = *claims.HubClientID, true, !super
+			}
+		}
+
+		if reference := c.Get(HubClientHeader); reference != "" {
+			hubClient, err := service.Resolve(reference)
+			if err != nil {
+				return err
+			}
+			if isBound && hubClient.ID != hubClientID {
+				return exceptions.Forbidden("Cannot act for another hub client", fiber.Map{"field": HubClientHeader, "value": reference})
+			}
+			hubClientID, ok = hubClient.ID, true
+		}
+
+		if ok {
+			c.Locals(HubClientIDKey, hubClientID)
+			c.SetUserContext(tenant.WithHubClientID(c.UserContext(), hubClientID))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+	"go-modules-api/internal/tenant"
+)
+
+// MockTenantService is a mock implementation of the TenantService interface.
+type MockTenantService struct {
+	mock.Mock
+}
+
+func (m *MockTenantService) Resolve(reference string) (*models.HubClient, error) {
+	args := m.Called(reference)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.HubClient), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func hubClient(id uint) *models.HubClient {
+	return &models.HubClient{BaseID: models.BaseID{ID: id}}
+}
+
+func TestTenant(t *testing.T) {
+	service := new(MockTenantService)
+	service.On("Resolve", "3").Return(hubClient(3), nil)
+	service.On("Resolve", "partner").Return(hubClient(4), nil)
+	service.On("Resolve", "unknown").Return(nil, exceptions.NotFound("Hub client not found", nil))
+
+	authorization := new(MockAuthorizationService)
+	authorization.On("Roles", uint(7)).Return([]uint{2}, false, nil)
+	authorization.On("Roles", uint(1)).Return([]uint{1}, true, nil)
+	authorizer := NewAuthorizer(authorization, true)
+
+	hubClientID := uint(3)
+	boundClaims := func(subject string) *services.Claims {
+		return &services.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: subject}, HubClientID: &hubClientID}
+	}
+	tests := []struct {
+		name    string
+		locals  map[string]interface{}
+		header  string
+		want    int
+		wantHub uint
+	}{
+		{name: "user selects a hub client", locals: map[string]interface{}{ClaimsKey: userClaims()}, header: "partner", want: fiber.StatusOK, wantHub: 4},
+		{name: "user without hub client", locals: map[string]interface{}{ClaimsKey: userClaims()}, want: fiber.StatusOK},
+		{name: "unknown hub client", locals: map[string]interface{}{ClaimsKey: userClaims()}, header: "unknown", want: fiber.StatusNotFound},
+		{name: "api key bound to its hub client", locals: map[string]interface{}{HubClientIDKey: uint(3)}, want: fiber.StatusOK, wantHub: 3},
+		{name: "api key naming its own hub client", locals: map[string]interface{}{HubClientIDKey: uint(3)}, header: "3", want: fiber.StatusOK, wantHub: 3},
+		{name: "api key acting for another hub client", locals: map[string]interface{}{HubClientIDKey: uint(3)}, header: "partner", want: fiber.StatusForbidden},
+		{name: "token bound to its hub client", locals: map[string]interface{}{ClaimsKey: boundClaims("7")}, want: fiber.StatusOK, wantHub: 3},
+		{name: "token bound to another hub client", locals: map[string]interface{}{ClaimsKey: boundClaims("7")}, header: "partner", want: fiber.StatusForbidden},
+		{name: "super role selects another hub client", locals: map[string]interface{}{ClaimsKey: boundClaims("1")}, header: "partner", want: fiber.StatusOK, wantHub: 4},
+		{name: "super role without header keeps its hub client", locals: map[string]interface{}{ClaimsKey: boundClaims("1")}, want: fiber.StatusOK, wantHub: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/roles", locals(tt.locals), Tenant(service, authorizer), func(c *fiber.Ctx) error {
+				got, found := tenant.HubClientID(c.UserContext())
+				assert.Equal(t, tt.wantHub != 0, found)
+				assert.Equal(t, tt.wantHub, got)
+				return ok(c)
+			})
+
+			headers := map[string]string{}
+			if tt.header != "" {
+				headers[HubClientHeader] = tt.header
+			}
+			assert.Equal(t, tt.want, status(t, app, "GET", "/roles", headers))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"strings"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/server/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Resource is implemented by handlers exposing the standard CRUD endpoints
+type Resource interface {
+	Paginate(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	GetByID(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	SoftDelete(c *fiber.Ctx) error
+}
+
+// RegisterResource defines the CRUD routes for a resource under path and returns
+// its group so resource-specific routes can be added.
+// The resource is named after the path: API keys need its "<resource>:read" or "<resource>:write"
+// scope and users a ModulePermission on the module of that type for the action of each route.
+func RegisterResource(router fiber.Router, path string, resource Resource, authz *middleware.Authorizer) fiber.Router {
+	module := strings.TrimPrefix(path, "/")
+	group := router.Group(path, middleware.RequireScope(module))
+
+	group.Get("/paginate", authz.Require(module, models.ActionRead), resource.Paginate)
+	group.Get("/", authz.Require(module, models.ActionRead), resource.List)
+	group.Post("/", authz.Require(module, models.ActionCreate), resource.Create)
+	group.Put("/:id", authz.Require(module, models.ActionUpdate), resource.Update)
+	group.Delete("/:id", authz.Require(module, models.ActionDelete), resource.SoftDelete)
+
+	group.Get("/:id", authz.Require(module, models.ActionRead), resource.GetByID)
+
+	return group
+}
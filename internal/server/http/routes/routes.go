@@ -1,12 +1,26 @@
 package routes
 
 import (
-	"github.com/gofiber/fiber/v2"
+	"fmt"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/metrics"
+	"go-modules-api/internal/ratelimit"
 	"go-modules-api/internal/server/container"
+	"go-modules-api/internal/server/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"go.uber.org/zap"
 )
 
-func SetupRoutes(app *fiber.App, log *zap.Logger, container *container.AppContainer) {
+// Guards holds the route level middleware shared by every API version
+type Guards struct {
+	Authorizer *middleware.Authorizer
+	Limiter    *middleware.RateLimiter
+}
+
+func SetupRoutes(app *fiber.App, log *zap.Logger, container *container.AppContainer) error {
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.Redirect("/docs")
 	})
@@ -15,7 +29,45 @@ func SetupRoutes(app *fiber.App, log *zap.Logger, container *container.AppContai
 		return c.SendFile("./docs/redoc.html")
 	})
 
-	HubClientsRoutes(app, container.Handlers.HubClientHandler)
-	RoleRoutes(app, container.Handlers.RoleHandler)
+	app.Get("/healthz", container.Handlers.HealthHandler.Live)
+	app.Get("/readyz", container.Handlers.HealthHandler.Ready)
+
+	if config.Env.MetricsEnabled {
+		allowedNetworks, err := middleware.ParseNetworks(config.Env.MetricsAllowedIPs)
+		if err != nil {
+			return fmt.Errorf("invalid METRICS_ALLOWED_IPS: %w", err)
+		}
+		if config.Env.MetricsToken == "" && len(allowedNetworks) == 0 {
+			log.Warn("Metrics are served without access restriction, set METRICS_TOKEN or METRICS_ALLOWED_IPS")
+		}
+		app.Get("/metrics", middleware.MetricsAccess(config.Env.MetricsToken, allowedNetworks), adaptor.HTTPHandler(metrics.Handler()))
+	}
+
+	policies, err := ratelimit.ParsePolicies(config.Env.RateLimits)
+	if err != nil {
+		return fmt.Errorf("invalid RATE_LIMITS: %w", err)
+	}
+
+	guards := &Guards{
+		Authorizer: middleware.NewAuthorizer(container.Services.AuthorizationService, config.Env.RbacEnabled),
+		Limiter:    middleware.NewRateLimiter(ratelimit.NewMemoryStore(), policies, config.Env.RateLimitEnabled, log),
+	}
+
+	// The IP is limited before authentication so floods of invalid credentials are limited too,
+	// the later limits refund its token when they reject the request
+	app.Use("/api", guards.Limiter.Limit("default", ratelimit.PrincipalIP))
+	app.Use("/api", middleware.ClientCertificate(container.Services.ClientCertificateService))
+	app.Use("/api", middleware.Authenticate(middleware.AuthConfig{
+		Enabled:      config.Env.AuthEnabled,
+		PublicRoutes: append(publicRoutes(apiVersions), config.Env.AuthPublicRoutes...),
+	},
+		middleware.APIKeyCredential(container.Services.APIKeyService),
+		middleware.OAuthCredential(container.Services.OAuthService),
+		middleware.JWTCredential(container.Services.TokenService, container.Services.RevocationService),
+	))
+	app.Use("/api", middleware.Tenant(container.Services.TenantService, guards.Authorizer))
+	app.Use("/api", guards.Limiter.Limit("default", ratelimit.PrincipalAPIKey, ratelimit.PrincipalHubClient))
+	app.Use("/api", middleware.Idempotency(container.Services.IdempotencyService, log))
 
+	return registerVersions(app, log, container, guards, apiVersions)
 }
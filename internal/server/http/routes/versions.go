@@ -0,0 +1,180 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/server/container"
+	"go-modules-api/internal/server/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// APIVersion describes a version of the API mounted under /api/<Name>.
+// Versions are registered side by side, so a new version only needs its own
+// Register function reusing or replacing the handlers of the previous one.
+type APIVersion struct {
+	Name     string
+	Register func(router fiber.Router, container *container.AppContainer, guards *Guards)
+
+	// Deprecation marks every route of the version as deprecated when set.
+	Deprecation *middleware.DeprecationConfig
+	// DeprecatedRoutes marks single routes as deprecated, their Route is relative to the version prefix
+	// like Public, e.g. "GET /roles/:id". They take precedence over Deprecation.
+	DeprecatedRoutes []middleware.DeprecatedRoute
+	// Public lists the "METHOD /path" routes, relative to the version prefix, reachable without authentication.
+	Public []string
+}
+
+// defaultVersion is the version also served under the unversioned /api prefix.
+// It must point to a version that is not deprecated as a whole, registerVersions fails otherwise.
+const defaultVersion = "v1"
+
+// apiVersions lists every version served by the API.
+var apiVersions = []APIVersion{
+	{
+		Name:     "v1",
+		Register: registerV1,
+		Public: []string{
+			"POST /auth/login", "POST /auth/refresh", "POST /auth/password/forgot", "POST /auth/password/reset",
+			"POST /oauth/token", "POST /oauth/introspect", "POST /oauth/revoke",
+		},
+	},
+}
+
+// registerV1 defines the routes of the v1 API
+func registerV1(router fiber.Router, container *container.AppContainer, guards *Guards) {
+	auth := router.Group("/auth", guards.Limiter.Limit("auth"))
+	auth.Post("/login", container.Handlers.AuthHandler.Login)
+	auth.Post("/refresh", container.Handlers.AuthHandler.Refresh)
+	auth.Post("/logout", middleware.RequireUser(), container.Handlers.AuthHandler.Logout)
+	auth.Put("/password", middleware.RequireUser(), container.Handlers.AuthHandler.ChangePassword)
+	auth.Post("/password/forgot", container.Handlers.AuthHandler.ForgotPassword)
+	auth.Post("/password/reset", container.Handlers.AuthHandler.ResetPassword)
+
+	oauth := router.Group("/oauth", guards.Limiter.Limit("auth"))
+	oauth.Post("/token", container.Handlers.OAuthHandler.Token)
+	oauth.Post("/introspect", container.Handlers.OAuthHandler.Introspect)
+	oauth.Post("/revoke", container.Handlers.OAuthHandler.Revoke)
+
+	authz := guards.Authorizer
+
+	hubClients := RegisterResource(router, "/hub_clients", container.Handlers.HubClientHandler, authz)
+	apiKeys := hubClients.Group("/:id/api_keys", middleware.RequireUser())
+	apiKeys.Get("/", authz.Require("api_keys", models.ActionRead), container.Handlers.APIKeyHandler.List)
+	apiKeys.Post("/", authz.Require("api_keys", models.ActionCreate), container.Handlers.APIKeyHandler.Create)
+	apiKeys.Post("/:key_id/rotate", authz.Require("api_keys", models.ActionUpdate), container.Handlers.APIKeyHandler.Rotate)
+	apiKeys.Delete("/:key_id", authz.Require("api_keys", models.ActionDelete), container.Handlers.APIKeyHandler.Revoke)
+
+	certificates := hubClients.Group("/:id/certificates", middleware.RequireUser())
+	certificates.Get("/", authz.Require("client_certificates", models.ActionRead), container.Handlers.ClientCertificateHandler.List)
+	certificates.Post("/", authz.Require("client_certificates", models.ActionCreate), container.Handlers.ClientCertificateHandler.Create)
+	certificates.Delete("/:certificate_id", authz.Require("client_certificates", models.ActionDelete), container.Handlers.ClientCertificateHandler.Revoke)
+
+	hubClients.Delete("/:id/sessions", middleware.RequireUser(), authz.Require("hub_clients", models.ActionUpdate), container.Handlers.SessionHandler.RevokeHubClient)
+
+	clientSecrets := hubClients.Group("/:id/client_secrets", middleware.RequireUser())
+	clientSecrets.Get("/", authz.Require("client_secrets", models.ActionRead), container.Handlers.OAuthHandler.ListSecrets)
+	clientSecrets.Post("/", authz.Require("client_secrets", models.ActionCreate), container.Handlers.OAuthHandler.CreateSecret)
+	clientSecrets.Delete("/:secret_id", authz.Require("client_secrets", models.ActionDelete), container.Handlers.OAuthHandler.RevokeSecret)
+
+	RegisterResource(router, "/roles", container.Handlers.RoleHandler, authz)
+	users := RegisterResource(router, "/users", container.Handlers.UserHandler, authz)
+	users.Delete("/:id/sessions", middleware.RequireUser(), authz.Require("users", models.ActionUpdate), container.Handlers.SessionHandler.RevokeUser)
+
+	// Operator endpoints act on the whole process, so no hub client role may reach them
+	admin := router.Group("/admin", middleware.RequireUser(), authz.RequireSuper())
+	admin.Get("/info", container.Handlers.AdminHandler.Info)
+	admin.Get("/log_level", container.Handlers.AdminHandler.LogLevel)
+	admin.Put("/log_level", container.Handlers.AdminHandler.SetLogLevel)
+	if config.Env.PprofEnabled && config.Env.PprofAddr == "" {
+		// Profiles are only served on the public port when no internal listener is configured
+		admin.Get("/debug/pprof/*", container.Handlers.AdminHandler.Profile)
+	}
+}
+
+// registerVersions mounts every API version under /api/<version> and the default
+// version under /api as an alias.
+func registerVersions(app *fiber.App, log *zap.Logger, container *container.AppContainer, guards *Guards, versions []APIVersion) error {
+	deprecated, err := deprecatedRoutes(versions)
+	if err != nil {
+		return err
+	}
+
+	api := app.Group("/api")
+
+	// Deprecations are matched on the full path rather than attached to the version groups, as the
+	// handlers of the /api alias group would run for every version
+	api.Use(middleware.Deprecation(log, deprecated))
+
+	for _, version := range versions {
+		version.Register(api.Group("/"+version.Name), container, guards)
+
+		if version.Name == defaultVersion {
+			version.Register(api.Group(""), container, guards)
+		}
+	}
+	return nil
+}
+
+// deprecatedRoutes returns the deprecated routes of every version with their full /api/<version>
+// prefix, and under /api for the route level deprecations of the default version.
+func deprecatedRoutes(versions []APIVersion) ([]middleware.DeprecatedRoute, error) {
+	var deprecated []middleware.DeprecatedRoute
+	for _, version := range versions {
+		prefixes := []string{"/api/" + version.Name}
+		if version.Name == defaultVersion {
+			if version.Deprecation != nil {
+				return nil, fmt.Errorf("the default API version %s is deprecated, point defaultVersion to a newer version", version.Name)
+			}
+			prefixes = append(prefixes, "/api")
+		}
+
+		for _, route := range version.DeprecatedRoutes {
+			pattern := route.Route
+			for _, prefix := range prefixes {
+				route.Route = prefixRoute(prefix, pattern)
+				route.Version = version.Name
+				deprecated = append(deprecated, route)
+			}
+		}
+
+		if version.Deprecation != nil {
+			cfg := *version.Deprecation
+			cfg.Version = version.Name
+			deprecated = append(deprecated, middleware.DeprecatedRoute{Route: "/api/" + version.Name + "/*", DeprecationConfig: cfg})
+		}
+	}
+	return deprecated, nil
+}
+
+// publicRoutes returns the public routes of every version with their full /api/<version>
+// prefix, and under /api for the default version.
+func publicRoutes(versions []APIVersion) []string {
+	var public []string
+	for _, version := range versions {
+		prefixes := []string{"/api/" + version.Name}
+		if version.Name == defaultVersion {
+			prefixes = append(prefixes, "/api")
+		}
+
+		for _, route := range version.Public {
+			for _, prefix := range prefixes {
+				public = append(public, prefixRoute(prefix, route))
+			}
+		}
+	}
+	return public
+}
+
+// prefixRoute adds a prefix to the path of a "METHOD /path" pattern
+func prefixRoute(prefix string, route string) string {
+	method, path, found := strings.Cut(route, " ")
+	if !found {
+		method, path = "", route
+	}
+	return strings.TrimSpace(method + " " + prefix + path)
+}
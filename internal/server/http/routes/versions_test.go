@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go-modules-api/internal/server/container"
+	"go-modules-api/internal/server/http/middleware"
+)
+
+// echoVersion registers GET /roles and GET /roles/:id answering the name of the version
+func echoVersion(name string) func(router fiber.Router, _ *container.AppContainer, _ *Guards) {
+	return func(router fiber.Router, _ *container.AppContainer, _ *Guards) {
+		router.Get("/roles", func(c *fiber.Ctx) error { return c.SendString(name) })
+		router.Get("/roles/:id", func(c *fiber.Ctx) error { return c.SendString(name) })
+	}
+}
+
+func TestRegisterVersions(t *testing.T) {
+	app := fiber.New()
+	err := registerVersions(app, zap.NewNop(), nil, nil, []APIVersion{
+		{Name: "v0", Register: echoVersion("v0"), Deprecation: &middleware.DeprecationConfig{}},
+		{Name: "v1", Register: echoVersion("v1"), DeprecatedRoutes: []middleware.DeprecatedRoute{{Route: "GET /roles/:id"}}},
+		{Name: "v2", Register: echoVersion("v2")},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path       string
+		version    string
+		deprecated bool
+	}{
+		{path: "/api/v0/roles", version: "v0", deprecated: true},
+		{path: "/api/v1/roles", version: "v1", deprecated: false},
+		{path: "/api/v1/roles/3", version: "v1", deprecated: true},
+		{path: "/api/v2/roles", version: "v2", deprecated: false},
+		{path: "/api/v2/roles/3", version: "v2", deprecated: false},
+		// The alias serves the default version with its route level deprecations
+		{path: "/api/roles", version: "v1", deprecated: false},
+		{path: "/api/roles/3", version: "v1", deprecated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil), -1)
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, string(body))
+			assert.Equal(t, tt.deprecated, resp.Header.Get("Deprecation") != "")
+		})
+	}
+}
+
+func TestRegisterVersions_DeprecatedDefaultVersion(t *testing.T) {
+	err := registerVersions(fiber.New(), zap.NewNop(), nil, nil, []APIVersion{
+		{Name: "v1", Register: echoVersion("v1"), Deprecation: &middleware.DeprecationConfig{}},
+		{Name: "v2", Register: echoVersion("v2")},
+	})
+	assert.ErrorContains(t, err, "default API version v1 is deprecated")
+}
+
+func TestPublicRoutes(t *testing.T) {
+	public := publicRoutes([]APIVersion{
+		{Name: "v1", Public: []string{"POST /auth/login"}},
+		{Name: "v2", Public: []string{"/docs"}},
+	})
+
+	assert.Equal(t, []string{"POST /api/v1/auth/login", "POST /api/auth/login", "/api/v2/docs"}, public)
+}
@@ -1,55 +1,332 @@
 package http
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
 	"go-modules-api/config"
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/server/container"
+	"go-modules-api/internal/server/http/handlers"
 	"go-modules-api/internal/server/http/middleware"
 	"go-modules-api/internal/server/http/routes"
+	"go-modules-api/utils"
 
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type Server struct {
-	App *fiber.App
-	Log *zap.Logger
+	App       *fiber.App
+	Log       *zap.Logger
+	Container *container.AppContainer
+
+	done    chan struct{}
+	workers sync.WaitGroup
 }
 
-func NewServer(log *zap.Logger) *Server {
+// NewServer creates the server, failing on an invalid configuration. Panics recovered while
+// handling requests are handed to the reporters.
+func NewServer(log *zap.Logger, reporters ...middleware.PanicReporter) (*Server, error) {
+	exceptions.LegacyFormat = config.Env.LegacyErrorFormat
+	handlers.CacheControl = config.Env.HttpCacheControl
+
+	bodyLimits, err := middleware.ParseBodyLimits(config.Env.HttpBodyLimit, config.Env.HttpRouteBodyLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_ROUTE_BODY_LIMITS: %w", err)
+	}
+
+	allowOrigins := strings.Join(config.Env.CorsAllowOrigins, ",")
+	if config.Env.CorsAllowCredentials && (allowOrigins == "" || allowOrigins == "*") {
+		return nil, errors.New("CORS_ALLOW_CREDENTIALS requires an explicit CORS_ALLOW_ORIGINS allowlist")
+	}
+
+	if config.Env.HttpProxyHeader != "" && len(config.Env.HttpTrustedProxies) == 0 {
+		return nil, errors.New("HTTP_PROXY_HEADER requires HTTP_TRUSTED_PROXIES, clients could spoof their IP otherwise")
+	}
+
 	app := fiber.New(fiber.Config{
 		AppName:      "go-modules-api",
-		BodyLimit:    1024 * 1024 * 1024, // 1GB
+		BodyLimit:    bodyLimits.Max(),
+		ReadTimeout:  config.Env.HttpReadTimeout,
+		WriteTimeout: config.Env.HttpWriteTimeout,
+		IdleTimeout:  config.Env.HttpIdleTimeout,
 		ErrorHandler: middleware.ErrorHandler,
 		JSONEncoder:  json.Marshal,
 		JSONDecoder:  json.Unmarshal,
+		// The client IP is only read from the proxy header on connections from trusted proxies
+		ProxyHeader:             config.Env.HttpProxyHeader,
+		EnableTrustedProxyCheck: len(config.Env.HttpTrustedProxies) > 0,
+		TrustedProxies:          config.Env.HttpTrustedProxies,
+		EnableIPValidation:      true,
 	})
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
-	}))
-	app.Use(middleware.RequestLogger(log))
+	// Metrics wraps Recover to count the recovered panics as 500, Recover comes before every other
+	// middleware so their panics are recovered too
+	app.Use(middleware.Metrics())
+	app.Use(middleware.Recover(log.Named("panic"), reporters...))
+	app.Use(middleware.Tracing())
+	app.Use(middleware.RequestID())
+	app.Use(securityHeaders(config.Env))
+	app.Use(corsPolicy(allowOrigins, config.Env.CorsAllowCredentials))
+	app.Use(middleware.RequestLogger(utils.SampledLogger(log, config.Env.LogRequestSampleInitial, config.Env.LogRequestSampleThereafter)))
+	app.Use(bodyLimits.Handler())
+
+	log.Named("http").Info("HTTP hardening settings",
+		zap.Strings("cors_allow_origins", config.Env.CorsAllowOrigins),
+		zap.Bool("cors_allow_credentials", config.Env.CorsAllowCredentials),
+		zap.Int("body_limit", bodyLimits.Default),
+		zap.Strings("route_body_limits", config.Env.HttpRouteBodyLimits),
+		zap.Duration("read_timeout", config.Env.HttpReadTimeout),
+		zap.Duration("write_timeout", config.Env.HttpWriteTimeout),
+		zap.Duration("idle_timeout", config.Env.HttpIdleTimeout),
+		zap.String("proxy_header", config.Env.HttpProxyHeader),
+		zap.Strings("trusted_proxies", config.Env.HttpTrustedProxies),
+		zap.Int("hsts_max_age", config.Env.HttpHstsMaxAge),
+		zap.String("frame_options", config.Env.HttpFrameOptions),
+		zap.String("content_security_policy", config.Env.HttpContentSecurityPolicy),
+	)
+
+	if config.Env.PprofEnabled {
+		runtime.SetBlockProfileRate(config.Env.PprofBlockProfileRate)
+		runtime.SetMutexProfileFraction(config.Env.PprofMutexProfileFraction)
+	}
 
 	app.Static("/", "./docs")
 
 	appContainer := container.NewAppContainer()
 
-	routes.SetupRoutes(app, log, appContainer)
+	if err := routes.SetupRoutes(app, log, appContainer); err != nil {
+		return nil, err
+	}
 
 	return &Server{
-		App: app,
-		Log: log,
-	}
+		App:       app,
+		Log:       log,
+		Container: appContainer,
+		done:      make(chan struct{}),
+	}, nil
 }
 
+// securityHeaders returns the middleware sending the security headers configured in cfg
+func securityHeaders(cfg *config.Config) fiber.Handler {
+	return helmet.New(helmet.Config{
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         cfg.HttpFrameOptions,
+		HSTSMaxAge:            cfg.HttpHstsMaxAge,
+		ContentSecurityPolicy: cfg.HttpContentSecurityPolicy,
+		// The docs page loads its bundle and fonts from CDNs that do not send CORP headers
+		CrossOriginEmbedderPolicy: "unsafe-none",
+	})
+}
+
+// corsPolicy returns the CORS middleware allowing the comma separated origins
+func corsPolicy(allowOrigins string, allowCredentials bool) fiber.Handler {
+	return cors.New(cors.Config{
+		AllowOrigins:     allowOrigins,
+		AllowCredentials: allowCredentials,
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Hub-Client, Idempotency-Key, If-None-Match, If-Modified-Since, Traceparent, Tracestate, X-Request-ID, X-Correlation-ID",
+		ExposeHeaders:    "X-Request-ID, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After",
+	})
+}
+
+// Start serves requests until SIGTERM or SIGINT, then shuts down gracefully
 func (s *Server) Start() {
 	port := fmt.Sprintf(":%d", config.Env.AppPort)
 	log := s.Log.Named("server")
 	log.Sugar().Infof("server is running at %s", port)
-	if err := s.App.Listen(port); err != nil {
+
+	s.background(func() {
+		s.cleanupExpired("idempotency", config.Env.IdempotencyCleanupInterval, s.Container.Services.IdempotencyService.DeleteExpired)
+	})
+	s.background(func() {
+		s.cleanupExpired("oauth", config.Env.OAuthTokenCleanupInterval, s.Container.Services.OAuthService.DeleteExpired)
+	})
+	s.background(func() {
+		s.cleanupExpired("revocation", config.Env.RevocationCleanupInterval, s.Container.Services.RevocationService.DeleteExpired)
+	})
+
+	s.background(s.toggleDebugOnSignal)
+
+	if config.Env.PprofEnabled && config.Env.PprofAddr != "" {
+		if err := s.serveProfiling(config.Env.PprofAddr, log); err != nil {
+			log.Fatal("error while starting the profiling server", zap.Error(err))
+		}
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	listener, err := s.listener(port, log)
+	if err != nil {
 		log.Fatal("error while starting server", zap.Error(err))
 	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.App.Listener(listener)
+	}()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			log.Fatal("error while starting server", zap.Error(err))
+		}
+	case sig := <-signals:
+		log.Info("Shutting down", zap.String("signal", sig.String()))
+		s.Shutdown(signals)
+	}
+}
+
+// Shutdown fails readiness, waits SHUTDOWN_DELAY for load balancers to notice unless a signal
+// arrives, drains in-flight requests within SHUTDOWN_TIMEOUT and stops the background workers
+func (s *Server) Shutdown(signals <-chan os.Signal) {
+	log := s.Log.Named("server")
+	s.Container.Health.Drain()
+
+	if config.Env.ShutdownDelay > 0 {
+		log.Info("Failing readiness before draining", zap.Duration("delay", config.Env.ShutdownDelay))
+		select {
+		case <-time.After(config.Env.ShutdownDelay):
+		case <-signals:
+			log.Info("Second signal received, draining now")
+		}
+	}
+
+	if err := s.App.ShutdownWithTimeout(config.Env.ShutdownTimeout); err != nil {
+		log.Error("In-flight requests did not finish in time", zap.Duration("timeout", config.Env.ShutdownTimeout), zap.Error(err))
+	}
+
+	close(s.done)
+	s.workers.Wait()
+	log.Info("Server stopped")
+}
+
+// listener listens on the port, with TLS when it is configured
+func (s *Server) listener(port string, log *zap.Logger) (net.Listener, error) {
+	listener, err := net.Listen("tcp", port)
+	if err != nil || !config.Env.TLSEnabled() {
+		return listener, err
+	}
+
+	files, err := config.NewTLSFiles()
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to load TLS files: %w", err)
+	}
+	s.background(func() {
+		files.Watch(config.Env.TLSReloadInterval, s.done, s.Log.Named("tls"))
+	})
+
+	log.Info("Serving HTTPS", zap.String("client_auth", config.Env.TLSClientAuth))
+	return tls.NewListener(listener, files.Config()), nil
+}
+
+// serveProfiling serves the pprof endpoints on an internal address, without authentication,
+// until the server stops
+func (s *Server) serveProfiling(addr string, log *zap.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	app := fiber.New(fiber.Config{
+		AppName:               "go-modules-api-pprof",
+		DisableStartupMessage: true,
+		ErrorHandler:          middleware.ErrorHandler,
+	})
+	app.Get("/debug/pprof/*", s.Container.Handlers.AdminHandler.Profile)
+
+	s.background(func() {
+		if err := app.Listener(listener); err != nil {
+			log.Error("Profiling server stopped", zap.Error(err))
+		}
+	})
+	s.background(func() {
+		<-s.done
+		if err := app.ShutdownWithTimeout(config.Env.ShutdownTimeout); err != nil {
+			log.Error("Profiles in progress did not finish in time", zap.Error(err))
+		}
+	})
+
+	if host, _, _ := net.SplitHostPort(addr); host == "" || net.ParseIP(host).IsUnspecified() {
+		log.Warn("Profiling endpoints are reachable on every interface, bind PPROF_ADDR to an internal address")
+	}
+	log.Info("Serving profiling endpoints", zap.String("addr", listener.Addr().String()))
+	return nil
+}
+
+// toggleDebugOnSignal switches the log level to debug on SIGUSR1, and back to the level it replaced
+// on the next one, until the server stops
+func (s *Server) toggleDebugOnSignal() {
+	if len(logLevelSignals) == 0 {
+		return
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, logLevelSignals...)
+	defer signal.Stop(signals)
+
+	log := s.Log.Named("server")
+	restore := utils.LogLevel.Level()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-signals:
+			level := utils.LogLevel.Level()
+			if level == zapcore.DebugLevel {
+				utils.LogLevel.SetLevel(restore)
+			} else {
+				restore = level
+				utils.LogLevel.SetLevel(zapcore.DebugLevel)
+			}
+			log.Warn("Log level changed", zap.Stringer("from", level), zap.Stringer("to", utils.LogLevel.Level()))
+		}
+	}
+}
+
+// background runs a worker that stops when the server shuts down
+func (s *Server) background(worker func()) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		worker()
+	}()
+}
+
+// cleanupExpired periodically deletes the expired records of a store until the server stops
+func (s *Server) cleanupExpired(name string, interval time.Duration, deleteExpired func() (int64, error)) {
+	log := s.Log.Named(name)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			deleted, err := deleteExpired()
+			if err != nil {
+				log.Error("Failed to delete expired records", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				log.Info("Deleted expired records", zap.Int64("count", deleted))
+			}
+		}
+	}
 }
@@ -0,0 +1,68 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/config"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	app := fiber.New()
+	app.Use(securityHeaders(&config.Config{
+		HttpHstsMaxAge:            31536000,
+		HttpFrameOptions:          "DENY",
+		HttpContentSecurityPolicy: "default-src 'self'",
+	}))
+	app.Get("/roles", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/roles", nil)
+	req.Header.Set(fiber.HeaderXForwardedProto, "https")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+	assert.Equal(t, "DENY", resp.Header.Get(fiber.HeaderXFrameOptions))
+	assert.Equal(t, "default-src 'self'", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+
+	// HSTS is only sent over HTTPS
+	resp, err = app.Test(httptest.NewRequest("GET", "/roles", nil), -1)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+}
+
+func TestCorsPolicy(t *testing.T) {
+	app := fiber.New()
+	app.Use(corsPolicy("https://app.example.com", true))
+	app.Get("/roles", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	preflight := func(origin string) *nethttp.Response {
+		req := httptest.NewRequest("OPTIONS", "/roles", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		req.Header.Set(fiber.HeaderAccessControlRequestMethod, "GET")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	allowed := preflight("https://app.example.com")
+	assert.Equal(t, fiber.StatusNoContent, allowed.StatusCode)
+	assert.Equal(t, "https://app.example.com", allowed.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", allowed.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	assert.Contains(t, allowed.Header.Get(fiber.HeaderAccessControlAllowHeaders), "Idempotency-Key")
+
+	denied := preflight("https://evil.example.com")
+	assert.Empty(t, denied.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+
+	req := httptest.NewRequest("GET", "/roles", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com", resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderAccessControlExposeHeaders), "X-Request-ID")
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// APIKeyPrefix starts every API key so it can be told apart from a JWT
+	APIKeyPrefix = "gma_"
+
+	// lastUsedResolution limits how often a key in constant use writes its last-used timestamp
+	lastUsedResolution = time.Minute
+)
+
+// APIKeyService defines business logic for hub client API keys
+type APIKeyService interface {
+	List(ctx context.Context, hubClientID uint) ([]models.APIKey, error)
+	Generate(ctx context.Context, hubClientID uint, payload *dto.CreateAPIKeyDTO) (*models.APIKey, string, error)
+	Revoke(ctx context.Context, hubClientID uint, id uint) error
+	Rotate(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, string, error)
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo          repositories.APIKeyRepository
+	hubClients    repositories.HubClientRepository
+	authorization AuthorizationService
+}
+
+// NewAPIKeyService creates an APIKeyService. Keys are only granted scopes the roles of their creator
+// hold, as decided by the authorization service.
+func NewAPIKeyService(repo repositories.APIKeyRepository, hubClients repositories.HubClientRepository, authorization AuthorizationService) APIKeyService {
+	return &apiKeyService{repo: repo, hubClients: hubClients, authorization: authorization}
+}
+
+// IsAPIKey reports whether a bearer token has the shape of an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// List returns every key of the hub client, revoked ones included
+func (s *apiKeyService) List(ctx context.Context, hubClientID uint) ([]models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.List")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.ListByHubClient(inTenant(ctx, hubClientID), hubClientID)
+	return keys, utils.HandleDBError(err)
+}
+
+// Generate creates a key for the hub client and returns it with its plaintext value,
+// which cannot be recovered afterwards. The scopes must be held by the caller.
+func (s *apiKeyService) Generate(ctx context.Context, hubClientID uint, payload *dto.CreateAPIKeyDTO) (*models.APIKey, string, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Generate")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, "", err
+	}
+	if err := checkScopes(ctx, s.authorization, hubClientID, payload.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	key, plaintext, err := newAPIKey(hubClientID, payload.Name, payload.Scopes, payload.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Create(inTenant(ctx, hubClientID), key); err != nil {
+		return nil, "", utils.HandleDBError(err)
+	}
+	return key, plaintext, nil
+}
+
+// Revoke disables a key of the hub client immediately
+func (s *apiKeyService) Revoke(ctx context.Context, hubClientID uint, id uint) error {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+
+	ctx = inTenant(ctx, hubClientID)
+	key, err := s.getKey(ctx, hubClientID, id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	return utils.HandleDBError(s.repo.Revoke(ctx, key, time.Now()))
+}
+
+// Rotate revokes a key and replaces it with a new one with the same name, scopes and expiry
+func (s *apiKeyService) Rotate(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, string, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Rotate")
+	defer span.End()
+
+	ctx = inTenant(ctx, hubClientID)
+	old, err := s.getKey(ctx, hubClientID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if old.RevokedAt != nil {
+		return nil, "", exceptions.BadRequest("API key is revoked", map[string]interface{}{"field": "id", "value": id})
+	}
+
+	key, plaintext, err := newAPIKey(hubClientID, old.Name, old.Scopes, old.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Rotate(ctx, old, key, time.Now()); err != nil {
+		return nil, "", utils.HandleDBError(err)
+	}
+	return key, plaintext, nil
+}
+
+// Authenticate returns the usable key matching the plaintext value of an active hub client.
+// The key is looked up across tenants, since its hub client is not known yet.
+func (s *apiKeyService) Authenticate(ctx context.Context, plaintext string) (*models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	prefix, ok := secretLookupPrefix(APIKeyPrefix, plaintext)
+	if !ok {
+		return nil, exceptions.Unauthorized("Invalid API key", nil)
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	key, err := s.repo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exceptions.Unauthorized("Invalid API key", nil)
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+
+	hash := hashSecret(plaintext)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.KeyHash)) != 1 {
+		return nil, exceptions.Unauthorized("Invalid API key", nil)
+	}
+
+	now := time.Now()
+	if !key.Usable(now) {
+		return nil, exceptions.Unauthorized("API key is revoked or expired", nil)
+	}
+
+	hubClient, err := s.hubClients.GetByID(key.HubClientID)
+	if err != nil || !hubClient.Active {
+		return nil, exceptions.Unauthorized("Invalid API key", nil)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, key, now); err != nil {
+			return nil, utils.HandleDBError(err)
+		}
+	}
+
+	return key, nil
+}
+
+// checkHubClient returns a 404 when the hub client does not exist
+func (s *apiKeyService) checkHubClient(hubClientID uint) error {
+	_, err := s.hubClients.GetByID(hubClientID)
+	return utils.HandleDBError(err)
+}
+
+// getKey returns a key of the hub client, or a 404
+func (s *apiKeyService) getKey(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, error) {
+	key, err := s.repo.GetByHubClient(ctx, hubClientID, id)
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+	return key, nil
+}
+
+// inTenant acts for the hub client owning the keys unless the request is already bound to a tenant,
+// in which case the keys of any other hub client stay out of reach
+func inTenant(ctx context.Context, hubClientID uint) context.Context {
+	if _, ok := tenant.HubClientID(ctx); ok {
+		return ctx
+	}
+	return tenant.WithHubClientID(ctx, hubClientID)
+}
+
+// newAPIKey builds a key of the form gma_<id>_<secret>, where gma_<id> is its stored prefix
+func newAPIKey(hubClientID uint, name string, scopes []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+	plaintext, prefix, err := newSecret(APIKeyPrefix)
+	if err != nil {
+		return nil, "", exceptions.InternalServerError("Failed to generate API key", nil)
+	}
+
+	return &models.APIKey{
+		HubClientID: hubClientID,
+		Name:        name,
+		Prefix:      prefix,
+		KeyHash:     hashSecret(plaintext),
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt,
+	}, plaintext, nil
+}
@@ -0,0 +1,220 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockAPIKeyRepository
+// ---------------------------
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.APIKey, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.APIKey, error) {
+	args := m.Called(hubClientID, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	args := m.Called(prefix)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, key *models.APIKey, at time.Time) error {
+	args := m.Called(key, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Rotate(ctx context.Context, old *models.APIKey, replacement *models.APIKey, at time.Time) error {
+	args := m.Called(old, replacement, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, key *models.APIKey, at time.Time) error {
+	args := m.Called(key, at)
+	return args.Error(0)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func TestGenerateAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read"))
+
+	mockHubClients.On("GetByID", uint(1)).Return(&models.HubClient{BaseID: models.BaseID{ID: 1}}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil)
+
+	key, plaintext, err := service.Generate(callerContext(), 1, &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: []string{"roles:read"}})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(plaintext, key.Prefix+"_"))
+	assert.True(t, services.IsAPIKey(plaintext))
+	assert.NotContains(t, key.KeyHash, plaintext)
+	assert.Len(t, key.KeyHash, 64)
+	assert.Equal(t, uint(1), key.HubClientID)
+	assert.Equal(t, []string{"roles:read"}, key.Scopes)
+
+	mockRepo.AssertExpectations(t)
+	mockHubClients.AssertExpectations(t)
+}
+
+func TestGenerateAPIKey_UnknownHubClient(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read"))
+
+	mockHubClients.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := service.Generate(context.Background(), 9, &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: []string{"*"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not_found")
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestGenerateAPIKey_ScopesBeyondCaller(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read", "users:read", "users:create"))
+
+	mockHubClients.On("GetByID", uint(1)).Return(&models.HubClient{BaseID: models.BaseID{ID: 1}}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		scopes    []string
+		forbidden bool
+	}{
+		{name: "every scope", ctx: callerContext(), scopes: []string{"*"}, forbidden: true},
+		{name: "ungranted scope", ctx: callerContext(), scopes: []string{"roles:read", "roles:write"}, forbidden: true},
+		{name: "partially granted write", ctx: callerContext(), scopes: []string{"users:write"}, forbidden: true},
+		{name: "every action of a module", ctx: callerContext(), scopes: []string{"users:*"}, forbidden: true},
+		{name: "without caller", ctx: context.Background(), scopes: []string{"roles:read"}, forbidden: true},
+		{name: "every scope without RBAC", ctx: services.WithRoles(context.Background(), nil, false), scopes: []string{"*"}, forbidden: true},
+		{name: "granted scopes", ctx: callerContext(), scopes: []string{"roles:read", "users:read"}},
+		{name: "any scope without RBAC", ctx: services.WithRoles(context.Background(), nil, false), scopes: []string{"roles:write"}},
+		{name: "super role", ctx: services.WithSuper(callerContext()), scopes: []string{"*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := service.Generate(tt.ctx, 1, &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: tt.scopes})
+			if tt.forbidden {
+				assertForbidden(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "Create", 3)
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read"))
+
+	mockHubClients.On("GetByID", uint(1)).Return(&models.HubClient{BaseID: models.BaseID{ID: 1}, Active: true}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil)
+	key, plaintext, err := service.Generate(callerContext(), 1, &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: []string{"roles:read"}})
+	require.NoError(t, err)
+
+	mockRepo.On("GetByPrefix", key.Prefix).Return(key, nil)
+	mockRepo.On("TouchLastUsed", key, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	authenticated, err := service.Authenticate(context.Background(), plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, key, authenticated)
+
+	// A wrong secret with a valid prefix is rejected
+	_, err = service.Authenticate(context.Background(), key.Prefix+"_deadbeef")
+	assert.Contains(t, err.Error(), "unauthorized")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticateAPIKey_Revoked(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read"))
+
+	mockHubClients.On("GetByID", uint(1)).Return(&models.HubClient{BaseID: models.BaseID{ID: 1}, Active: true}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil)
+	key, plaintext, err := service.Generate(callerContext(), 1, &dto.CreateAPIKeyDTO{Name: "Integration", Scopes: []string{"roles:read"}})
+	require.NoError(t, err)
+
+	revokedAt := time.Now().Add(-time.Minute)
+	key.RevokedAt = &revokedAt
+	mockRepo.On("GetByPrefix", key.Prefix).Return(key, nil)
+
+	_, err = service.Authenticate(context.Background(), plaintext)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewAPIKeyService(mockRepo, mockHubClients, scopeAuthorization(1, "roles:read"))
+
+	old := &models.APIKey{BaseID: models.BaseID{ID: 3}, HubClientID: 1, Name: "Integration", Prefix: "gma_000000000000", Scopes: []string{"menus:write"}}
+	mockRepo.On("GetByHubClient", uint(1), uint(3)).Return(old, nil)
+	mockRepo.On("Rotate", old, mock.AnythingOfType("*models.APIKey"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	key, plaintext, err := service.Rotate(context.Background(), 1, 3)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.Prefix, key.Prefix)
+	assert.Equal(t, old.Name, key.Name)
+	assert.Equal(t, old.Scopes, key.Scopes)
+	assert.True(t, strings.HasPrefix(plaintext, key.Prefix+"_"))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	key := &models.APIKey{Scopes: []string{"roles:read", "menus:write", "products:*"}}
+
+	assert.True(t, key.HasScope("roles:read"))
+	assert.False(t, key.HasScope("roles:write"))
+	assert.True(t, key.HasScope("menus:read"))
+	assert.True(t, key.HasScope("menus:write"))
+	assert.True(t, key.HasScope("products:write"))
+	assert.False(t, key.HasScope("hub_clients:read"))
+	assert.True(t, (&models.APIKey{Scopes: []string{"*"}}).HasScope("hub_clients:write"))
+}
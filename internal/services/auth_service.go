@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// dummyPasswordHash is compared against when the user does not exist, so unknown
+// emails take as long to reject as wrong passwords
+const dummyPasswordHash = "$2a$10$ItXCgFxRGqhrlzAfcauuqenbx/orsqmWqmJ5ZmCnF1j5DAOzemoYO"
+
+// AuthService defines business logic for user authentication
+type AuthService interface {
+	Login(ctx context.Context, email string, password string) (*models.User, *dto.AuthTokensDTO, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.User, *dto.AuthTokensDTO, error)
+	Logout(ctx context.Context, claims *Claims, refreshToken string) error
+}
+
+type authService struct {
+	repo        repositories.UserRepository
+	tokens      TokenService
+	revocations RevocationService
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+func NewAuthService(repo repositories.UserRepository, tokens TokenService, revocations RevocationService, accessTTL time.Duration, refreshTTL time.Duration) AuthService {
+	return &authService{repo: repo, tokens: tokens, revocations: revocations, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// Login checks the user credentials and issues a new token pair.
+// Users sign in before their hub client is known, so they are looked up across tenants.
+func (s *authService) Login(ctx context.Context, email string, password string) (*models.User, *dto.AuthTokensDTO, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	user, err := s.repo.GetByEmail(tenant.WithoutScope(ctx), email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.CheckPassword(dummyPasswordHash, password)
+		return nil, nil, exceptions.Unauthorized("Invalid user credentials", nil)
+	}
+	if err != nil {
+		return nil, nil, utils.HandleDBError(err)
+	}
+
+	if !utils.CheckPassword(user.Password, password) {
+		return nil, nil, exceptions.Unauthorized("Invalid user credentials", nil)
+	}
+
+	tokens, err := s.issueTokens(user)
+	return user, tokens, err
+}
+
+// Refresh exchanges a valid and unrevoked refresh token of an active user for a new token pair
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*models.User, *dto.AuthTokensDTO, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Refresh")
+	defer span.End()
+
+	claims, err := s.tokens.Verify(refreshToken, RefreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.revocations.Check(ctx, claims); err != nil {
+		return nil, nil, err
+	}
+
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	user, err := s.repo.WithContext(tenant.WithoutScope(ctx)).GetByID(uint(id))
+	if err != nil || !user.Active {
+		return nil, nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	tokens, err := s.issueTokens(user)
+	return user, tokens, err
+}
+
+// Logout revokes the access token with the claims and, when given, a refresh token of the same user
+func (s *authService) Logout(ctx context.Context, claims *Claims, refreshToken string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	if refreshToken != "" {
+		refreshClaims, err := s.tokens.Verify(refreshToken, RefreshToken)
+		if err != nil {
+			return err
+		}
+		if refreshClaims.Subject != claims.Subject {
+			return exceptions.Forbidden("The refresh token belongs to another user", nil)
+		}
+		if err := s.revocations.RevokeToken(ctx, refreshClaims); err != nil {
+			return err
+		}
+	}
+
+	return s.revocations.RevokeToken(ctx, claims)
+}
+
+// issueTokens creates an access and refresh token pair for the user, binding the access token
+// to the hub client of the user. The refresh token carries the hub client too, so revoking the
+// sessions of the hub client revokes it.
+func (s *authService) issueTokens(user *models.User) (*dto.AuthTokensDTO, error) {
+	subject := strconv.FormatUint(uint64(user.ID), 10)
+
+	accessToken, err := s.tokens.Issue(&Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: subject},
+		TokenType:        AccessToken,
+		Email:            user.Email,
+		HubClientID:      &user.HubClientID,
+	}, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.tokens.Issue(&Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: subject},
+		TokenType:        RefreshToken,
+		HubClientID:      &user.HubClientID,
+	}, s.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthTokensDTO{
+		TokenType:    "Bearer",
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,332 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+)
+
+// ---------------------------
+// MockUserRepository
+// ---------------------------
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) WithContext(ctx context.Context) repositories.ResourceRepositoryInterface[models.User] {
+	return m
+}
+
+func (m *MockUserRepository) Pagination(search string, active *bool, sortField string, sortOrder string, page int, pageSize int) ([]models.User, int64, error) {
+	args := m.Called(search, active, sortField, sortOrder, page, pageSize)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) GetAll(search string, active *bool, sortField string, sortOrder string) ([]models.User, error) {
+	args := m.Called(search, active, sortField, sortOrder)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) LastModified(search string, active *bool) (int64, time.Time, error) {
+	args := m.Called(search, active)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockUserRepository) GetByID(id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) CreateWithRoles(ctx context.Context, user *models.User, roleIDs []uint) error {
+	args := m.Called(user, roleIDs)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateWithRoles(ctx context.Context, user *models.User, roleIDs *[]uint) error {
+	args := m.Called(user, roleIDs)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	args := m.Called(userID, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Create(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Update(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SoftDelete(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// ---------------------------
+// MockRevocationService
+// ---------------------------
+
+type MockRevocationService struct {
+	mock.Mock
+}
+
+func (m *MockRevocationService) RevokeToken(ctx context.Context, claims *services.Claims) error {
+	args := m.Called(claims)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) RevokeUser(ctx context.Context, userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) RevokeHubClient(ctx context.Context, hubClientID uint) error {
+	args := m.Called(hubClientID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) Check(ctx context.Context, claims *services.Claims) error {
+	args := m.Called(claims)
+	return args.Error(0)
+}
+
+func (m *MockRevocationService) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func newTestTokenService() services.TokenService {
+	secret := []byte("test-secret-of-at-least-32-bytes")
+	return services.NewTokenService(&config.JWTKeys{
+		SigningMethod: jwt.SigningMethodHS256,
+		SigningKey:    secret,
+		VerifyKeys:    map[string]interface{}{"": secret},
+	}, "go-modules-api", "")
+}
+
+func newTestUser(t *testing.T) *models.User {
+	password, err := utils.HashPassword("secret")
+	require.NoError(t, err)
+	return &models.User{
+		BaseID:         models.BaseID{ID: 7},
+		HubClientID:    3,
+		Email:          "user@example.com",
+		Password:       password,
+		BaseAttributes: models.BaseAttributes{Active: true},
+	}
+}
+
+func assertUnauthorized(t *testing.T, err error) {
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 401, apiErr.Status)
+}
+
+func TestLogin_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	tokens := newTestTokenService()
+	service := services.NewAuthService(mockRepo, tokens, new(MockRevocationService), time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+
+	loggedIn, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, user, loggedIn)
+	assert.Equal(t, "Bearer", issued.TokenType)
+	assert.Equal(t, int64(60), issued.ExpiresIn)
+
+	claims, err := tokens.Verify(issued.AccessToken, services.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	assert.Equal(t, user.Email, claims.Email)
+	require.NotNil(t, claims.HubClientID)
+	assert.Equal(t, uint(3), *claims.HubClientID)
+
+	_, err = tokens.Verify(issued.RefreshToken, services.AccessToken)
+	assertUnauthorized(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), new(MockRevocationService), time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+
+	_, _, err := service.Login(context.Background(), user.Email, "wrong")
+	assertUnauthorized(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogin_UnknownUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), new(MockRevocationService), time.Minute, time.Hour)
+
+	mockRepo.On("GetByEmail", "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := service.Login(context.Background(), "nobody@example.com", "secret")
+	assertUnauthorized(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRefresh_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	revocations := new(MockRevocationService)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), revocations, time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+	mockRepo.On("GetByID", user.ID).Return(user, nil)
+	revocations.On("Check", mock.AnythingOfType("*services.Claims")).Return(nil)
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+
+	_, refreshed, err := service.Refresh(context.Background(), issued.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEmpty(t, refreshed.AccessToken)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRefresh_RejectsAccessToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), new(MockRevocationService), time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+
+	_, _, err = service.Refresh(context.Background(), issued.AccessToken)
+	assertUnauthorized(t, err)
+}
+
+func TestRefresh_InactiveUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	revocations := new(MockRevocationService)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), revocations, time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+
+	inactive := *user
+	inactive.Active = false
+	mockRepo.On("GetByID", user.ID).Return(&inactive, nil)
+	revocations.On("Check", mock.AnythingOfType("*services.Claims")).Return(nil)
+
+	_, _, err = service.Refresh(context.Background(), issued.RefreshToken)
+	assertUnauthorized(t, err)
+}
+
+func TestRefresh_RevokedToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	revocations := new(MockRevocationService)
+	service := services.NewAuthService(mockRepo, newTestTokenService(), revocations, time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+	revocations.On("Check", mock.AnythingOfType("*services.Claims")).Return(exceptions.Unauthorized("Token has been revoked", nil))
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+
+	_, _, err = service.Refresh(context.Background(), issued.RefreshToken)
+	assertUnauthorized(t, err)
+
+	mockRepo.AssertNotCalled(t, "GetByID", user.ID)
+}
+
+func TestLogout_RevokesBothTokens(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	revocations := new(MockRevocationService)
+	tokens := newTestTokenService()
+	service := services.NewAuthService(mockRepo, tokens, revocations, time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+	revocations.On("RevokeToken", mock.AnythingOfType("*services.Claims")).Return(nil)
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+	accessClaims, err := tokens.Verify(issued.AccessToken, services.AccessToken)
+	require.NoError(t, err)
+
+	require.NoError(t, service.Logout(context.Background(), accessClaims, issued.RefreshToken))
+
+	require.Len(t, revocations.Calls, 2)
+	refreshClaims := revocations.Calls[0].Arguments.Get(0).(*services.Claims)
+	assert.Equal(t, services.RefreshToken, refreshClaims.TokenType)
+	require.NotNil(t, refreshClaims.HubClientID)
+	assert.Equal(t, uint(3), *refreshClaims.HubClientID)
+	assert.Equal(t, accessClaims, revocations.Calls[1].Arguments.Get(0))
+}
+
+func TestLogout_RejectsRefreshTokenOfAnotherUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	revocations := new(MockRevocationService)
+	tokens := newTestTokenService()
+	service := services.NewAuthService(mockRepo, tokens, revocations, time.Minute, time.Hour)
+
+	user := newTestUser(t)
+	mockRepo.On("GetByEmail", user.Email).Return(user, nil)
+
+	_, issued, err := service.Login(context.Background(), user.Email, "secret")
+	require.NoError(t, err)
+
+	err = service.Logout(context.Background(), &services.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "8"}}, issued.RefreshToken)
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 403, apiErr.Status)
+
+	revocations.AssertNotCalled(t, "RevokeToken", mock.Anything)
+}
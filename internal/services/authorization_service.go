@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/utils"
+)
+
+// AuthorizationService defines the role based access control decisions
+type AuthorizationService interface {
+	Roles(userID uint) (roleIDs []uint, super bool, err error)
+	Allowed(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error)
+}
+
+type authorizationService struct {
+	roles       repositories.RoleRepository
+	permissions repositories.ModulePermissionRepository
+}
+
+// NewAuthorizationService creates an AuthorizationService.
+// Users holding a super role are granted every action.
+func NewAuthorizationService(roles repositories.RoleRepository, permissions repositories.ModulePermissionRepository) AuthorizationService {
+	return &authorizationService{roles: roles, permissions: permissions}
+}
+
+// Roles returns the IDs of the active roles of the user and whether one of them is a super role
+func (s *authorizationService) Roles(userID uint) ([]uint, bool, error) {
+	roles, err := s.roles.GetByUser(userID)
+	if err != nil {
+		return nil, false, utils.HandleDBError(err)
+	}
+
+	super := false
+	roleIDs := make([]uint, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+		if role.Super {
+			super = true
+		}
+	}
+	return roleIDs, super, nil
+}
+
+// Allowed reports whether a ModulePermission grants the action on the module to one of the roles
+func (s *authorizationService) Allowed(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error) {
+	allowed, err := s.permissions.HasGrant(roleIDs, module, action, hubClientID)
+	return allowed, utils.HandleDBError(err)
+}
+
+// superKey is the context key marking requests made by a holder of a super role
+type superKey struct{}
+
+// WithSuper marks the context of a request made by a holder of a super role
+func WithSuper(ctx context.Context) context.Context {
+	return context.WithValue(ctx, superKey{}, true)
+}
+
+// IsSuper reports whether the context belongs to a request made by a holder of a super role
+func IsSuper(ctx context.Context) bool {
+	super, _ := ctx.Value(superKey{}).(bool)
+	return super
+}
+
+// requireSuper rejects changes reserved to holders of a super role
+func requireSuper(ctx context.Context) error {
+	if !IsSuper(ctx) {
+		return exceptions.Forbidden("Only holders of the super role can change super roles or their assignments", nil)
+	}
+	return nil
+}
+
+// rolesKey is the context key holding the roles of the user making the request
+type rolesKey struct{}
+
+// callerRoles are the roles of the user making a request
+type callerRoles struct {
+	ids      []uint
+	enforced bool
+}
+
+// WithRoles records the roles of the user making the request, which bound the scopes of the
+// credentials they create. The roles are not enforced when role based access control is disabled.
+func WithRoles(ctx context.Context, roleIDs []uint, enforced bool) context.Context {
+	return context.WithValue(ctx, rolesKey{}, callerRoles{ids: roleIDs, enforced: enforced})
+}
+
+// scopeActions returns the actions a scope grants on its resource: "write" and "*" also grant read,
+// like models.ScopesGrant
+func scopeActions(action string) []string {
+	if action == models.ActionRead {
+		return []string{models.ActionRead}
+	}
+	return []string{models.ActionRead, models.ActionCreate, models.ActionUpdate, models.ActionDelete}
+}
+
+// checkScopes rejects credential scopes granting more than the roles of the user making the request
+// hold on the hub client, so credentials never escalate the privileges of their creator. Only holders
+// of a super role may grant "*".
+func checkScopes(ctx context.Context, authorization AuthorizationService, hubClientID uint, scopes []string) error {
+	if IsSuper(ctx) {
+		return nil
+	}
+
+	roles, ok := ctx.Value(rolesKey{}).(callerRoles)
+	for _, scope := range scopes {
+		details := map[string]interface{}{"field": "scopes", "value": scope}
+		if scope == "*" {
+			return exceptions.Forbidden("Only holders of a super role can grant every scope", details)
+		}
+		if !ok {
+			return exceptions.Forbidden("You do not have permission to grant this scope", details)
+		}
+		if !roles.enforced {
+			continue
+		}
+
+		resource, action, _ := strings.Cut(scope, ":")
+		for _, granted := range scopeActions(action) {
+			allowed, err := authorization.Allowed(roles.ids, resource, granted, &hubClientID)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return exceptions.Forbidden("You do not have permission to grant this scope", details)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockModulePermissionRepository
+// ---------------------------
+
+type MockModulePermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockModulePermissionRepository) HasGrant(roleIDs []uint, module string, action string, hubClientID *uint) (bool, error) {
+	args := m.Called(roleIDs, module, action, hubClientID)
+	return args.Bool(0), args.Error(1)
+}
+
+// scopeAuthorization returns an AuthorizationService granting the role 2 the "module:action"
+// permissions on the hub client, and nothing else
+func scopeAuthorization(hubClientID uint, grants ...string) services.AuthorizationService {
+	mockPermissions := new(MockModulePermissionRepository)
+	for _, grant := range grants {
+		module, action, _ := strings.Cut(grant, ":")
+		mockPermissions.On("HasGrant", []uint{2}, module, action, &hubClientID).Return(true, nil)
+	}
+	mockPermissions.On("HasGrant", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	return services.NewAuthorizationService(new(MockRoleRepository), mockPermissions)
+}
+
+// callerContext is the context of a request made by a user holding the role 2 with RBAC enabled
+func callerContext() context.Context {
+	return services.WithRoles(context.Background(), []uint{2}, true)
+}
+
+func assertForbidden(t *testing.T, err error) {
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 403, apiErr.Status)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func TestAuthorizationRoles(t *testing.T) {
+	mockRoles := new(MockRoleRepository)
+	service := services.NewAuthorizationService(mockRoles, new(MockModulePermissionRepository))
+
+	mockRoles.On("GetByUser", uint(1)).Return([]models.Role{{BaseID: models.BaseID{ID: 2}, Slug: "admin"}}, nil)
+	mockRoles.On("GetByUser", uint(2)).Return([]models.Role{{BaseID: models.BaseID{ID: 3}, Slug: "operators", Super: true}}, nil)
+
+	roleIDs, super, err := service.Roles(1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{2}, roleIDs)
+	assert.False(t, super)
+
+	roleIDs, super, err = service.Roles(2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, roleIDs)
+	assert.True(t, super)
+
+	mockRoles.AssertExpectations(t)
+}
+
+func TestAuthorizationAllowed(t *testing.T) {
+	mockPermissions := new(MockModulePermissionRepository)
+	service := services.NewAuthorizationService(new(MockRoleRepository), mockPermissions)
+
+	mockPermissions.On("HasGrant", []uint{2}, "roles", models.ActionRead, (*uint)(nil)).Return(true, nil)
+	mockPermissions.On("HasGrant", []uint{2}, "roles", models.ActionDelete, (*uint)(nil)).Return(false, nil)
+
+	allowed, err := service.Allowed([]uint{2}, "roles", models.ActionRead, nil)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = service.Allowed([]uint{2}, "roles", models.ActionDelete, nil)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	mockPermissions.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BaseServiceInterface defines the common business operations for any resource type.
+type BaseServiceInterface[T any] interface {
+	Paginate(params dto.PaginatedDTO) ([]T, int64, error)
+	List(params dto.ListDTO) ([]T, error)
+	LastModified(params dto.ListDTO) (int64, time.Time, error)
+	GetByID(id uint) (*T, error)
+	Create(entity *T) error
+	Update(entity *T) error
+	Delete(id uint) error
+	SoftDelete(entity *T) error
+	WithContext(ctx context.Context) BaseServiceInterface[T]
+}
+
+// BaseService provides the common business operations on top of a ResourceRepositoryInterface.
+type BaseService[T any] struct {
+	repo repositories.ResourceRepositoryInterface[T]
+	ctx  context.Context
+	name string
+}
+
+// NewBaseService creates a new BaseService for the given repository.
+func NewBaseService[T any](repo repositories.ResourceRepositoryInterface[T]) *BaseService[T] {
+	return &BaseService[T]{repo: repo, ctx: context.Background(), name: reflect.TypeFor[T]().Name() + "Service"}
+}
+
+// WithContext returns a copy of the service bound to the request context
+func (s *BaseService[T]) WithContext(ctx context.Context) BaseServiceInterface[T] {
+	return s.withContext(ctx)
+}
+
+// withContext returns a copy of the service bound to the context, for services embedding it
+func (s *BaseService[T]) withContext(ctx context.Context) *BaseService[T] {
+	return &BaseService[T]{repo: s.repo, ctx: ctx, name: s.name}
+}
+
+// trace starts the span of an operation and returns the repository bound to it, so the
+// statements of the operation are traced as its children
+func (s *BaseService[T]) trace(operation string) (repositories.ResourceRepositoryInterface[T], trace.Span) {
+	ctx, span := tracing.Start(s.ctx, s.name+"."+operation)
+	return s.repo.WithContext(ctx), span
+}
+
+// Paginate retrieves a page of records
+func (s *BaseService[T]) Paginate(params dto.PaginatedDTO) ([]T, int64, error) {
+	repo, span := s.trace("Paginate")
+	defer span.End()
+
+	entities, total, err := repo.Pagination(
+		params.Search,
+		params.Active,
+		params.SortField,
+		params.SortOrder,
+		params.Page,
+		params.PageSize,
+	)
+	return entities, total, utils.HandleDBError(err)
+}
+
+// List returns all records with filtering and sorting
+func (s *BaseService[T]) List(params dto.ListDTO) ([]T, error) {
+	repo, span := s.trace("List")
+	defer span.End()
+
+	entities, err := repo.GetAll(params.Search, params.Active, params.SortField, params.SortOrder)
+	return entities, utils.HandleDBError(err)
+}
+
+// LastModified returns the count and latest update time of the records matching the filters
+func (s *BaseService[T]) LastModified(params dto.ListDTO) (int64, time.Time, error) {
+	repo, span := s.trace("LastModified")
+	defer span.End()
+
+	count, lastModified, err := repo.LastModified(params.Search, params.Active)
+	return count, lastModified, utils.HandleDBError(err)
+}
+
+// GetByID retrieves a record by ID
+func (s *BaseService[T]) GetByID(id uint) (*T, error) {
+	repo, span := s.trace("GetByID")
+	defer span.End()
+
+	entity, err := repo.GetByID(id)
+	return entity, utils.HandleDBError(err)
+}
+
+// Create creates a new record
+func (s *BaseService[T]) Create(entity *T) error {
+	repo, span := s.trace("Create")
+	defer span.End()
+
+	return utils.HandleDBError(repo.Create(entity))
+}
+
+// Update updates an existing record
+func (s *BaseService[T]) Update(entity *T) error {
+	repo, span := s.trace("Update")
+	defer span.End()
+
+	return utils.HandleDBError(repo.Update(entity))
+}
+
+// Delete removes a record
+func (s *BaseService[T]) Delete(id uint) error {
+	repo, span := s.trace("Delete")
+	defer span.End()
+
+	return utils.HandleDBError(repo.Delete(id))
+}
+
+// SoftDelete marks a record as deleted
+func (s *BaseService[T]) SoftDelete(entity *T) error {
+	repo, span := s.trace("SoftDelete")
+	defer span.End()
+
+	return utils.HandleDBError(repo.SoftDelete(entity))
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// ClientCertificateService defines the mapping of mutual TLS client certificates to hub clients
+type ClientCertificateService interface {
+	List(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error)
+	Create(ctx context.Context, hubClientID uint, payload *dto.CreateClientCertificateDTO) (*models.ClientCertificate, error)
+	Revoke(ctx context.Context, hubClientID uint, id uint) error
+	Authenticate(ctx context.Context, certificate *x509.Certificate, verified bool) (*models.ClientCertificate, error)
+}
+
+type clientCertificateService struct {
+	repo          repositories.ClientCertificateRepository
+	hubClients    repositories.HubClientRepository
+	authorization AuthorizationService
+}
+
+// NewClientCertificateService creates a ClientCertificateService. Certificates are only granted scopes
+// the roles of their creator hold, as decided by the authorization service.
+func NewClientCertificateService(repo repositories.ClientCertificateRepository, hubClients repositories.HubClientRepository, authorization AuthorizationService) ClientCertificateService {
+	return &clientCertificateService{repo: repo, hubClients: hubClients, authorization: authorization}
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of a certificate in lowercase hex
+func CertificateFingerprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// List returns every certificate of the hub client, revoked ones included
+func (s *clientCertificateService) List(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error) {
+	ctx, span := tracing.Start(ctx, "ClientCertificateService.List")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, err
+	}
+	certificates, err := s.repo.ListByHubClient(inTenant(ctx, hubClientID), hubClientID)
+	return certificates, utils.HandleDBError(err)
+}
+
+// Create maps a certificate fingerprint or subject to the hub client. The scopes must be held by the caller.
+func (s *clientCertificateService) Create(ctx context.Context, hubClientID uint, payload *dto.CreateClientCertificateDTO) (*models.ClientCertificate, error) {
+	ctx, span := tracing.Start(ctx, "ClientCertificateService.Create")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, err
+	}
+	if err := checkScopes(ctx, s.authorization, hubClientID, payload.Scopes); err != nil {
+		return nil, err
+	}
+
+	certificate := &models.ClientCertificate{
+		HubClientID: hubClientID,
+		Name:        payload.Name,
+		Fingerprint: strings.ToLower(strings.ReplaceAll(payload.Fingerprint, ":", "")),
+		Subject:     payload.Subject,
+		Scopes:      payload.Scopes,
+		ExpiresAt:   payload.ExpiresAt,
+	}
+	if err := s.repo.Create(inTenant(ctx, hubClientID), certificate); err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+	return certificate, nil
+}
+
+// Revoke stops a certificate of the hub client from authenticating immediately
+func (s *clientCertificateService) Revoke(ctx context.Context, hubClientID uint, id uint) error {
+	ctx, span := tracing.Start(ctx, "ClientCertificateService.Revoke")
+	defer span.End()
+
+	ctx = inTenant(ctx, hubClientID)
+	certificate, err := s.repo.GetByHubClient(ctx, hubClientID, id)
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	if certificate.RevokedAt != nil {
+		return nil
+	}
+	return utils.HandleDBError(s.repo.Revoke(ctx, certificate, time.Now()))
+}
+
+// Authenticate returns the mapping of the peer certificate of a connection, or nil when it has none.
+// Subjects can be forged by anyone able to issue a certificate, so they only match certificates
+// verified against the client CA bundle; fingerprints always match.
+func (s *clientCertificateService) Authenticate(ctx context.Context, peer *x509.Certificate, verified bool) (*models.ClientCertificate, error) {
+	ctx, span := tracing.Start(ctx, "ClientCertificateService.Authenticate")
+	defer span.End()
+
+	now := time.Now()
+	if now.Before(peer.NotBefore) || now.After(peer.NotAfter) {
+		return nil, exceptions.Unauthorized("Client certificate is not valid at this time", nil)
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	certificate, err := s.repo.GetByFingerprint(ctx, CertificateFingerprint(peer))
+	if subject := peer.Subject.String(); errors.Is(err, gorm.ErrRecordNotFound) && verified && subject != "" {
+		certificate, err = s.repo.GetBySubject(ctx, subject)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+
+	if !certificate.Usable(now) {
+		return nil, exceptions.Unauthorized("Client certificate is revoked or expired", nil)
+	}
+
+	hubClient, err := s.hubClients.GetByID(certificate.HubClientID)
+	if err != nil || !hubClient.Active {
+		return nil, exceptions.Unauthorized("Invalid client certificate", nil)
+	}
+
+	if certificate.LastUsedAt == nil || now.Sub(*certificate.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, certificate, now); err != nil {
+			return nil, utils.HandleDBError(err)
+		}
+	}
+
+	return certificate, nil
+}
+
+// checkHubClient returns a 404 when the hub client does not exist
+func (s *clientCertificateService) checkHubClient(hubClientID uint) error {
+	_, err := s.hubClients.GetByID(hubClientID)
+	return utils.HandleDBError(err)
+}
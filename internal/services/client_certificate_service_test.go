@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockClientCertificateRepository
+// ---------------------------
+
+type MockClientCertificateRepository struct {
+	mock.Mock
+}
+
+func (m *MockClientCertificateRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.ClientCertificate, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.ClientCertificate), args.Error(1)
+}
+
+func (m *MockClientCertificateRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.ClientCertificate, error) {
+	args := m.Called(hubClientID, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ClientCertificate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClientCertificateRepository) GetByFingerprint(ctx context.Context, fingerprint string) (*models.ClientCertificate, error) {
+	args := m.Called(fingerprint)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ClientCertificate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClientCertificateRepository) GetBySubject(ctx context.Context, subject string) (*models.ClientCertificate, error) {
+	args := m.Called(subject)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ClientCertificate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClientCertificateRepository) Create(ctx context.Context, certificate *models.ClientCertificate) error {
+	args := m.Called(certificate)
+	return args.Error(0)
+}
+
+func (m *MockClientCertificateRepository) Revoke(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error {
+	args := m.Called(certificate, at)
+	return args.Error(0)
+}
+
+func (m *MockClientCertificateRepository) TouchLastUsed(ctx context.Context, certificate *models.ClientCertificate, at time.Time) error {
+	args := m.Called(certificate, at)
+	return args.Error(0)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+// newTestCertificate creates a self-signed certificate for "CN=partner,O=Acme" valid until notAfter
+func newTestCertificate(t *testing.T, notAfter time.Time) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "partner", Organization: []string{"Acme"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate
+}
+
+func newCertificateService() (services.ClientCertificateService, *MockClientCertificateRepository, *MockHubClientRepository) {
+	mockRepo := new(MockClientCertificateRepository)
+	mockHubClients := new(MockHubClientRepository)
+	mockHubClients.On("GetByID", uint(4)).Return(&models.HubClient{BaseID: models.BaseID{ID: 4}, Active: true}, nil)
+	mockRepo.On("TouchLastUsed", mock.AnythingOfType("*models.ClientCertificate"), mock.AnythingOfType("time.Time")).Return(nil)
+	return services.NewClientCertificateService(mockRepo, mockHubClients, scopeAuthorization(4, "roles:read")), mockRepo, mockHubClients
+}
+
+func TestClientCertificateAuthenticate_Fingerprint(t *testing.T) {
+	service, mockRepo, _ := newCertificateService()
+	peer := newTestCertificate(t, time.Now().Add(time.Hour))
+
+	mapping := &models.ClientCertificate{HubClientID: 4, Fingerprint: services.CertificateFingerprint(peer)}
+	mockRepo.On("GetByFingerprint", mapping.Fingerprint).Return(mapping, nil)
+
+	certificate, err := service.Authenticate(context.Background(), peer, false)
+	require.NoError(t, err)
+	assert.Equal(t, mapping, certificate)
+	mockRepo.AssertCalled(t, "TouchLastUsed", mapping, mock.AnythingOfType("time.Time"))
+}
+
+func TestClientCertificateAuthenticate_SubjectRequiresVerification(t *testing.T) {
+	service, mockRepo, _ := newCertificateService()
+	peer := newTestCertificate(t, time.Now().Add(time.Hour))
+
+	mapping := &models.ClientCertificate{HubClientID: 4, Subject: "CN=partner,O=Acme"}
+	mockRepo.On("GetByFingerprint", services.CertificateFingerprint(peer)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetBySubject", "CN=partner,O=Acme").Return(mapping, nil)
+
+	certificate, err := service.Authenticate(context.Background(), peer, false)
+	require.NoError(t, err)
+	assert.Nil(t, certificate)
+	mockRepo.AssertNotCalled(t, "GetBySubject", mock.Anything)
+
+	certificate, err = service.Authenticate(context.Background(), peer, true)
+	require.NoError(t, err)
+	assert.Equal(t, mapping, certificate)
+}
+
+func TestClientCertificateAuthenticate_Rejected(t *testing.T) {
+	service, mockRepo, _ := newCertificateService()
+
+	expired := newTestCertificate(t, time.Now().Add(-time.Minute))
+	_, err := service.Authenticate(context.Background(), expired, true)
+	assertUnauthorized(t, err)
+
+	peer := newTestCertificate(t, time.Now().Add(time.Hour))
+	expiredAt := time.Now().Add(-time.Minute)
+	mockRepo.On("GetByFingerprint", services.CertificateFingerprint(peer)).
+		Return(&models.ClientCertificate{HubClientID: 4, ExpiresAt: &expiredAt}, nil)
+
+	_, err = service.Authenticate(context.Background(), peer, true)
+	assertUnauthorized(t, err)
+}
+
+func TestClientCertificateCreate_NormalizesFingerprint(t *testing.T) {
+	service, mockRepo, _ := newCertificateService()
+	mockRepo.On("Create", mock.AnythingOfType("*models.ClientCertificate")).Return(nil)
+
+	fingerprint := "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89"
+	certificate, err := service.Create(callerContext(), 4, &dto.CreateClientCertificateDTO{
+		Name:        "Partner gateway",
+		Fingerprint: fingerprint,
+		Scopes:      []string{"roles:read"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789", certificate.Fingerprint)
+	assert.Equal(t, uint(4), certificate.HubClientID)
+}
+
+func TestClientCertificateCreate_ScopesBeyondCaller(t *testing.T) {
+	service, mockRepo, _ := newCertificateService()
+
+	for _, scopes := range [][]string{{"*"}, {"roles:write"}, {"users:read"}} {
+		_, err := service.Create(callerContext(), 4, &dto.CreateClientCertificateDTO{
+			Name:        "Partner gateway",
+			Fingerprint: "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789",
+			Scopes:      scopes,
+		})
+		assertForbidden(t, err)
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
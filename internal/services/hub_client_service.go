@@ -1,72 +1,48 @@
 package services
 
 import (
-	"go-modules-api/internal/dto"
+	"context"
+
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/models"
 	"go-modules-api/internal/repositories"
-	"go-modules-api/utils"
+	"go-modules-api/internal/tenant"
 )
 
 // HubClientService defines business logic for hub clients
 type HubClientService interface {
-	PaginateHubClients(params dto.PaginatedHubClientDTO) ([]models.HubClient, int64, error)
-	ListHubClients(search string, active *bool, sortField string, sortOrder string) ([]models.HubClient, error)
-	GetHubClientByID(id uint) (*models.HubClient, error)
-	CreateHubClient(hubClient *models.HubClient) error
-	UpdateHubClient(hubClient *models.HubClient) error
-	DeleteHubClient(id uint) error
-	SoftDeleteHubClient(hubClient *models.HubClient) error
+	BaseServiceInterface[models.HubClient]
 }
 
+// hubClientService limits callers bound to a hub client to their own, unless they hold a super role.
+// Hub clients are the tenants, so the tenant GORM callbacks do not scope them.
 type hubClientService struct {
-	repo repositories.HubClientRepository
+	*BaseService[models.HubClient]
+	repo  repositories.HubClientRepository
+	bound bool
 }
 
 func NewHubClientService(repo repositories.HubClientRepository) HubClientService {
-	return &hubClientService{repo: repo}
-}
-
-// PaginateHubClients retrieves paginated hub clients
-func (s *hubClientService) PaginateHubClients(params dto.PaginatedHubClientDTO) ([]models.HubClient, int64, error) {
-	clients, total, err := s.repo.Pagination(
-		params.Search,
-		params.Active,
-		params.SortField,
-		params.SortOrder,
-		params.Page,
-		params.PageSize,
-	)
-	return clients, total, utils.HandleDBError(err)
-}
-
-// ListHubClients returns all hub clients with filtering and sorting
-func (s *hubClientService) ListHubClients(search string, active *bool, sortField string, sortOrder string) ([]models.HubClient, error) {
-	clients, err := s.repo.GetAll(search, active, sortField, sortOrder)
-	return clients, utils.HandleDBError(err)
+	return &hubClientService{BaseService: NewBaseService[models.HubClient](repo), repo: repo}
 }
 
-// GetHubClientByID retrieves a hub client by ID
-func (s *hubClientService) GetHubClientByID(id uint) (*models.HubClient, error) {
-	client, err := s.repo.GetByID(id)
-	return client, utils.HandleDBError(err)
-}
-
-// CreateHubClient creates a new hub client
-func (s *hubClientService) CreateHubClient(hubClient *models.HubClient) error {
-	return utils.HandleDBError(s.repo.Create(hubClient))
-}
-
-// UpdateHubClient updates an existing hub client
-func (s *hubClientService) UpdateHubClient(hubClient *models.HubClient) error {
-	return utils.HandleDBError(s.repo.Update(hubClient))
-}
+// WithContext returns a copy of the service bound to the request context, only seeing the hub
+// client of the context when it has one and the caller holds no super role
+func (s *hubClientService) WithContext(ctx context.Context) BaseServiceInterface[models.HubClient] {
+	service := s.BaseService.withContext(ctx)
+	hubClientID, bound := tenant.HubClientID(ctx)
+	if !bound || IsSuper(ctx) {
+		return &hubClientService{BaseService: service, repo: s.repo}
+	}
 
-// DeleteHubClient removes a hub client
-func (s *hubClientService) DeleteHubClient(id uint) error {
-	return utils.HandleDBError(s.repo.Delete(id))
+	service.repo = s.repo.ScopedTo(hubClientID)
+	return &hubClientService{BaseService: service, repo: s.repo, bound: true}
 }
 
-// SoftDeleteHubClient marks a hub client as deleted
-func (s *hubClientService) SoftDeleteHubClient(hubClient *models.HubClient) error {
-	return utils.HandleDBError(s.repo.SoftDelete(hubClient))
+// Create creates a hub client, which callers bound to another one cannot do
+func (s *hubClientService) Create(hubClient *models.HubClient) error {
+	if s.bound {
+		return exceptions.Forbidden("Cannot create hub clients while acting for one", nil)
+	}
+	return s.BaseService.Create(hubClient)
 }
@@ -1,15 +1,22 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
 	"go-modules-api/internal/services"
+	"go-modules-api/internal/tenant"
 )
 
 // ---------------------------
@@ -20,6 +27,15 @@ type MockHubClientRepository struct {
 	mock.Mock
 }
 
+func (m *MockHubClientRepository) WithContext(ctx context.Context) repositories.ResourceRepositoryInterface[models.HubClient] {
+	return m
+}
+
+func (m *MockHubClientRepository) ScopedTo(id uint) repositories.ResourceRepositoryInterface[models.HubClient] {
+	args := m.Called(id)
+	return args.Get(0).(repositories.ResourceRepositoryInterface[models.HubClient])
+}
+
 func (m *MockHubClientRepository) Pagination(search string, active *bool, sortField string, sortOrder string, page int, pageSize int) ([]models.HubClient, int64, error) {
 	args := m.Called(search, active, sortField, sortOrder, page, pageSize)
 	return args.Get(0).([]models.HubClient), args.Get(1).(int64), args.Error(2)
@@ -30,6 +46,11 @@ func (m *MockHubClientRepository) GetAll(search string, active *bool, sortField
 	return args.Get(0).([]models.HubClient), args.Error(1)
 }
 
+func (m *MockHubClientRepository) LastModified(search string, active *bool) (int64, time.Time, error) {
+	args := m.Called(search, active)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
 func (m *MockHubClientRepository) GetByID(id uint) (*models.HubClient, error) {
 	args := m.Called(id)
 	if args.Get(0) != nil {
@@ -53,6 +74,14 @@ func (m *MockHubClientRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockHubClientRepository) GetByExternalID(externalID string) (*models.HubClient, error) {
+	args := m.Called(externalID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.HubClient), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockHubClientRepository) SoftDelete(hubClient *models.HubClient) error {
 	args := m.Called(hubClient)
 	return args.Error(0)
@@ -66,7 +95,7 @@ func TestPaginateHubClients_Success(t *testing.T) {
 	mockRepo := new(MockHubClientRepository)
 	service := services.NewHubClientService(mockRepo)
 
-	params := dto.PaginatedHubClientDTO{
+	params := dto.PaginatedDTO{
 		Search:    "test",
 		Active:    nil,
 		SortField: "name",
@@ -85,7 +114,7 @@ func TestPaginateHubClients_Success(t *testing.T) {
 		On("Pagination", params.Search, params.Active, params.SortField, params.SortOrder, params.Page, params.PageSize).
 		Return(expectedClients, expectedTotal, nil)
 
-	clients, total, err := service.PaginateHubClients(params)
+	clients, total, err := service.Paginate(params)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedClients, clients)
 	assert.Equal(t, expectedTotal, total)
@@ -97,7 +126,7 @@ func TestPaginateHubClients_Error(t *testing.T) {
 	mockRepo := new(MockHubClientRepository)
 	service := services.NewHubClientService(mockRepo)
 
-	params := dto.PaginatedHubClientDTO{
+	params := dto.PaginatedDTO{
 		Search:    "",
 		Active:    nil,
 		SortField: "name",
@@ -111,7 +140,7 @@ func TestPaginateHubClients_Error(t *testing.T) {
 		On("Pagination", params.Search, params.Active, params.SortField, params.SortOrder, params.Page, params.PageSize).
 		Return([]models.HubClient{}, int64(0), expectedError)
 
-	_, _, err := service.PaginateHubClients(params)
+	_, _, err := service.Paginate(params)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -135,7 +164,7 @@ func TestListHubClients_Success(t *testing.T) {
 		On("GetAll", search, active, sortField, sortOrder).
 		Return(expectedClients, nil)
 
-	clients, err := service.ListHubClients(search, active, sortField, sortOrder)
+	clients, err := service.List(dto.ListDTO{Search: search, Active: active, SortField: sortField, SortOrder: sortOrder})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedClients, clients)
 
@@ -156,7 +185,7 @@ func TestListHubClients_Error(t *testing.T) {
 		On("GetAll", search, active, sortField, sortOrder).
 		Return([]models.HubClient{}, expectedError)
 
-	_, err := service.ListHubClients(search, active, sortField, sortOrder)
+	_, err := service.List(dto.ListDTO{Search: search, Active: active, SortField: sortField, SortOrder: sortOrder})
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -173,7 +202,7 @@ func TestGetHubClientByID_Success(t *testing.T) {
 		On("GetByID", clientID).
 		Return(expectedClient, nil)
 
-	client, err := service.GetHubClientByID(clientID)
+	client, err := service.GetByID(clientID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedClient, client)
 
@@ -190,7 +219,7 @@ func TestGetHubClientByID_Error(t *testing.T) {
 		On("GetByID", clientID).
 		Return(nil, expectedError)
 
-	client, err := service.GetHubClientByID(clientID)
+	client, err := service.GetByID(clientID)
 	assert.Error(t, err)
 	assert.Nil(t, client)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
@@ -207,7 +236,7 @@ func TestCreateHubClient_Success(t *testing.T) {
 		On("Create", newClient).
 		Return(nil)
 
-	err := service.CreateHubClient(newClient)
+	err := service.Create(newClient)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -223,7 +252,7 @@ func TestCreateHubClient_Error(t *testing.T) {
 		On("Create", newClient).
 		Return(expectedError)
 
-	err := service.CreateHubClient(newClient)
+	err := service.Create(newClient)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -239,7 +268,7 @@ func TestUpdateHubClient_Success(t *testing.T) {
 		On("Update", clientToUpdate).
 		Return(nil)
 
-	err := service.UpdateHubClient(clientToUpdate)
+	err := service.Update(clientToUpdate)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -255,7 +284,7 @@ func TestUpdateHubClient_Error(t *testing.T) {
 		On("Update", clientToUpdate).
 		Return(expectedError)
 
-	err := service.UpdateHubClient(clientToUpdate)
+	err := service.Update(clientToUpdate)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -271,7 +300,7 @@ func TestDeleteHubClient_Success(t *testing.T) {
 		On("Delete", clientID).
 		Return(nil)
 
-	err := service.DeleteHubClient(clientID)
+	err := service.Delete(clientID)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -287,9 +316,44 @@ func TestDeleteHubClient_Error(t *testing.T) {
 		On("Delete", clientID).
 		Return(expectedError)
 
-	err := service.DeleteHubClient(clientID)
+	err := service.Delete(clientID)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestHubClients_BoundCallerOnlySeesItsOwn(t *testing.T) {
+	mockRepo := new(MockHubClientRepository)
+	scopedRepo := new(MockHubClientRepository)
+	service := services.NewHubClientService(mockRepo)
+
+	mockRepo.On("ScopedTo", uint(4)).Return(scopedRepo)
+	scopedRepo.On("GetByID", uint(5)).Return(nil, gorm.ErrRecordNotFound)
+	scopedRepo.On("GetByID", uint(4)).Return(&models.HubClient{BaseID: models.BaseID{ID: 4}}, nil)
+
+	ctx := tenant.WithHubClientID(context.Background(), 4)
+	bound := service.WithContext(ctx)
+
+	_, err := bound.GetByID(5)
+	var apiErr *exceptions.APIException
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 404, apiErr.Status)
+
+	hubClient, err := bound.GetByID(4)
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), hubClient.ID)
+
+	err = bound.Create(&models.HubClient{Name: "Other"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 403, apiErr.Status)
+
+	// Super callers see every hub client
+	mockRepo.On("GetByID", uint(5)).Return(&models.HubClient{BaseID: models.BaseID{ID: 5}}, nil)
+	hubClient, err = service.WithContext(services.WithSuper(ctx)).GetByID(5)
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), hubClient.ID)
+
+	mockRepo.AssertNumberOfCalls(t, "ScopedTo", 1)
+	scopedRepo.AssertExpectations(t)
+}
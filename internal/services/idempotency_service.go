@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// reserveAttempts bounds how many times Begin retries when a concurrent request deletes the key it collided with
+const reserveAttempts = 3
+
+// IdempotencyOwner identifies the caller an Idempotency-Key belongs to: one caller can never
+// replay the response of another
+type IdempotencyOwner struct {
+	Principal   string
+	HubClientID uint
+}
+
+// IdempotencyService defines business logic for Idempotency-Key handling
+type IdempotencyService interface {
+	Begin(owner IdempotencyOwner, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error)
+	Complete(record *models.IdempotencyKey, statusCode int, contentType string, headers map[string]string, body []byte) error
+	Release(record *models.IdempotencyKey) error
+	DeleteExpired() (int64, error)
+}
+
+type idempotencyService struct {
+	repo repositories.IdempotencyKeyRepository
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyKeyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin reserves the key of the owner for a new request. When the key already holds a completed
+// response for the same request, that record is returned with replay set to true.
+func (s *idempotencyService) Begin(owner IdempotencyOwner, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error) {
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			Principal:         owner.Principal,
+			CallerHubClientID: owner.HubClientID,
+			Key:               key,
+			Method:            method,
+			Path:              path,
+			RequestHash:       requestHash,
+			ExpiresAt:         now.Add(s.ttl),
+		}
+
+		reserved, err := s.repo.Reserve(record)
+		if err != nil {
+			return nil, false, utils.HandleDBError(err)
+		}
+		if reserved {
+			return record, false, nil
+		}
+
+		existing, err := s.repo.GetByKey(owner.Principal, owner.HubClientID, key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released or expired between the insert and the lookup
+			continue
+		}
+		if err != nil {
+			return nil, false, utils.HandleDBError(err)
+		}
+
+		if existing.ExpiresAt.Before(now) {
+			if err := s.repo.Delete(existing); err != nil {
+				return nil, false, utils.HandleDBError(err)
+			}
+			continue
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, false, idempotencyError(http.StatusUnprocessableEntity, "idempotency_key_reused",
+				"Idempotency-Key was already used with a different request", key)
+		}
+
+		if !existing.Completed {
+			return nil, false, idempotencyError(http.StatusConflict, "idempotency_key_in_progress",
+				"A request with this Idempotency-Key is still being processed", key)
+		}
+
+		return existing, true, nil
+	}
+
+	return nil, false, idempotencyError(http.StatusConflict, "idempotency_key_in_progress",
+		"A request with this Idempotency-Key is still being processed", key)
+}
+
+// Complete stores the response of the request that reserved the key, with the headers replayed along its body
+func (s *idempotencyService) Complete(record *models.IdempotencyKey, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	record.Completed = true
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseHeaders = headers
+	record.ResponseBody = body
+	return utils.HandleDBError(s.repo.Update(record))
+}
+
+// Release frees the key so the request can be retried
+func (s *idempotencyService) Release(record *models.IdempotencyKey) error {
+	return utils.HandleDBError(s.repo.Delete(record))
+}
+
+// DeleteExpired removes every expired key
+func (s *idempotencyService) DeleteExpired() (int64, error) {
+	deleted, err := s.repo.DeleteExpired(time.Now())
+	return deleted, utils.HandleDBError(err)
+}
+
+// idempotencyError creates an APIException referencing the offending key
+func idempotencyError(status int, code, message, key string) *exceptions.APIException {
+	return exceptions.NewAPIException(status, code, message, map[string]string{"field": "Idempotency-Key", "value": key})
+}
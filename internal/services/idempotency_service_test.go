@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockIdempotencyKeyRepository
+// ---------------------------
+
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyKeyRepository) Reserve(record *models.IdempotencyKey) (bool, error) {
+	args := m.Called(record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) GetByKey(principal string, hubClientID uint, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(principal, hubClientID, key)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Update(record *models.IdempotencyKey) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Delete(record *models.IdempotencyKey) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+var owner = services.IdempotencyOwner{Principal: "user:7", HubClientID: 3}
+
+func TestIdempotencyBegin_Reserved(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(true, nil)
+
+	record, replay, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	assert.NoError(t, err)
+	assert.False(t, replay)
+	assert.Equal(t, "key", record.Key)
+	assert.Equal(t, "user:7", record.Principal)
+	assert.Equal(t, uint(3), record.CallerHubClientID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), record.ExpiresAt, time.Minute)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_Replay(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	stored := &models.IdempotencyKey{
+		Key:          "key",
+		RequestHash:  "hash",
+		Completed:    true,
+		StatusCode:   201,
+		ResponseBody: []byte(`{"id":1}`),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(false, nil)
+	mockRepo.On("GetByKey", "user:7", uint(3), "key").Return(stored, nil)
+
+	record, replay, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	assert.NoError(t, err)
+	assert.True(t, replay)
+	assert.Equal(t, stored, record)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_DifferentPayload(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	stored := &models.IdempotencyKey{Key: "key", RequestHash: "other", Completed: true, ExpiresAt: time.Now().Add(time.Hour)}
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(false, nil)
+	mockRepo.On("GetByKey", "user:7", uint(3), "key").Return(stored, nil)
+
+	_, _, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	var apiErr *exceptions.APIException
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 422, apiErr.Status)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_InProgress(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	stored := &models.IdempotencyKey{Key: "key", RequestHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(false, nil)
+	mockRepo.On("GetByKey", "user:7", uint(3), "key").Return(stored, nil)
+
+	_, _, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	var apiErr *exceptions.APIException
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 409, apiErr.Status)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_Expired(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	expired := &models.IdempotencyKey{Key: "key", RequestHash: "other", Completed: true, ExpiresAt: time.Now().Add(-time.Minute)}
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(false, nil).Once()
+	mockRepo.On("GetByKey", "user:7", uint(3), "key").Return(expired, nil).Once()
+	mockRepo.On("Delete", expired).Return(nil)
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(true, nil).Once()
+
+	_, replay, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	assert.NoError(t, err)
+	assert.False(t, replay)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_ReleasedConcurrently(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(false, nil).Once()
+	mockRepo.On("GetByKey", "user:7", uint(3), "key").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("Reserve", mock.AnythingOfType("*models.IdempotencyKey")).Return(true, nil).Once()
+
+	_, replay, err := service.Begin(owner, "key", "POST", "/api/roles", "hash")
+	assert.NoError(t, err)
+	assert.False(t, replay)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyComplete(t *testing.T) {
+	mockRepo := new(MockIdempotencyKeyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	record := &models.IdempotencyKey{Key: "key"}
+	mockRepo.On("Update", record).Return(nil)
+
+	err := service.Complete(record, 201, "application/json", map[string]string{"Location": "/api/roles/1"}, []byte(`{"id":1}`))
+	assert.NoError(t, err)
+	assert.True(t, record.Completed)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, map[string]string{"Location": "/api/roles/1"}, record.ResponseHeaders)
+	assert.Equal(t, []byte(`{"id":1}`), record.ResponseBody)
+
+	mockRepo.AssertExpectations(t)
+}
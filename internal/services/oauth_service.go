@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// OAuthClientSecretPrefix starts every OAuth2 client secret
+	OAuthClientSecretPrefix = "gmcs_"
+	// OAuthTokenPrefix starts every OAuth2 access token so it can be told apart from a JWT or an API key
+	OAuthTokenPrefix = "gmo_"
+
+	oauthTokenBytes = 32
+)
+
+// OAuth2 error codes of RFC 6749 section 5.2
+const (
+	OAuthInvalidRequest       = "invalid_request"
+	OAuthInvalidClient        = "invalid_client"
+	OAuthInvalidScope         = "invalid_scope"
+	OAuthUnsupportedGrantType = "unsupported_grant_type"
+)
+
+// OAuthService defines the OAuth2 client credentials flow of hub clients and the management
+// of their client secrets. The client_id of a hub client is its ExternalID.
+type OAuthService interface {
+	ListSecrets(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error)
+	CreateSecret(ctx context.Context, hubClientID uint, payload *dto.CreateOAuthClientSecretDTO) (*models.OAuthClientSecret, string, error)
+	RevokeSecret(ctx context.Context, hubClientID uint, id uint) error
+
+	IssueToken(ctx context.Context, clientID string, clientSecret string, scope string) (*dto.OAuthTokenDTO, error)
+	Introspect(ctx context.Context, clientID string, clientSecret string, token string) (*dto.OAuthIntrospectionDTO, error)
+	RevokeToken(ctx context.Context, clientID string, clientSecret string, token string) error
+	Authenticate(ctx context.Context, token string) (*models.OAuthAccessToken, error)
+	DeleteExpired() (int64, error)
+}
+
+type oauthService struct {
+	secrets       repositories.OAuthClientSecretRepository
+	tokens        repositories.OAuthAccessTokenRepository
+	hubClients    repositories.HubClientRepository
+	authorization AuthorizationService
+	tokenTTL      time.Duration
+}
+
+// NewOAuthService creates an OAuthService. Client secrets are only granted scopes the roles of their
+// creator hold, as decided by the authorization service.
+func NewOAuthService(secrets repositories.OAuthClientSecretRepository, tokens repositories.OAuthAccessTokenRepository, hubClients repositories.HubClientRepository, authorization AuthorizationService, tokenTTL time.Duration) OAuthService {
+	return &oauthService{secrets: secrets, tokens: tokens, hubClients: hubClients, authorization: authorization, tokenTTL: tokenTTL}
+}
+
+// IsOAuthToken reports whether a bearer token has the shape of an OAuth2 access token
+func IsOAuthToken(token string) bool {
+	return strings.HasPrefix(token, OAuthTokenPrefix)
+}
+
+// OAuthError creates an APIException carrying an RFC 6749 error code
+func OAuthError(status int, code string, description string) *exceptions.APIException {
+	return exceptions.NewAPIException(status, code, description, nil)
+}
+
+// ListSecrets returns every secret of the hub client, revoked ones included
+func (s *oauthService) ListSecrets(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error) {
+	ctx, span := tracing.Start(ctx, "OAuthService.ListSecrets")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, err
+	}
+	secrets, err := s.secrets.ListByHubClient(inTenant(ctx, hubClientID), hubClientID)
+	return secrets, utils.HandleDBError(err)
+}
+
+// CreateSecret registers a secret for the hub client and returns it with its plaintext value,
+// which cannot be recovered afterwards. The scopes must be held by the caller.
+func (s *oauthService) CreateSecret(ctx context.Context, hubClientID uint, payload *dto.CreateOAuthClientSecretDTO) (*models.OAuthClientSecret, string, error) {
+	ctx, span := tracing.Start(ctx, "OAuthService.CreateSecret")
+	defer span.End()
+
+	if err := s.checkHubClient(hubClientID); err != nil {
+		return nil, "", err
+	}
+	if err := checkScopes(ctx, s.authorization, hubClientID, payload.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	plaintext, prefix, err := newSecret(OAuthClientSecretPrefix)
+	if err != nil {
+		return nil, "", exceptions.InternalServerError("Failed to generate client secret", nil)
+	}
+
+	secret := &models.OAuthClientSecret{
+		HubClientID: hubClientID,
+		Name:        payload.Name,
+		Prefix:      prefix,
+		SecretHash:  hashSecret(plaintext),
+		Scopes:      payload.Scopes,
+		ExpiresAt:   payload.ExpiresAt,
+	}
+	if err := s.secrets.Create(inTenant(ctx, hubClientID), secret); err != nil {
+		return nil, "", utils.HandleDBError(err)
+	}
+	return secret, plaintext, nil
+}
+
+// RevokeSecret disables a secret of the hub client and the access tokens issued with it
+func (s *oauthService) RevokeSecret(ctx context.Context, hubClientID uint, id uint) error {
+	ctx, span := tracing.Start(ctx, "OAuthService.RevokeSecret")
+	defer span.End()
+
+	ctx = inTenant(ctx, hubClientID)
+	secret, err := s.secrets.GetByHubClient(ctx, hubClientID, id)
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	if secret.RevokedAt != nil {
+		return nil
+	}
+	return utils.HandleDBError(s.secrets.Revoke(ctx, secret, time.Now()))
+}
+
+// IssueToken authenticates the client and issues an access token for the requested space-separated
+// scopes, or for every scope of the secret when none is requested
+func (s *oauthService) IssueToken(ctx context.Context, clientID string, clientSecret string, scope string) (*dto.OAuthTokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "OAuthService.IssueToken")
+	defer span.End()
+
+	secret, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		scopes = secret.Scopes
+	}
+	for _, requested := range scopes {
+		if !models.ScopesGrant(secret.Scopes, requested) {
+			return nil, OAuthError(http.StatusBadRequest, OAuthInvalidScope, "The requested scope exceeds the scopes of the client secret")
+		}
+	}
+
+	plaintext, err := randomHex(oauthTokenBytes)
+	if err != nil {
+		return nil, exceptions.InternalServerError("Failed to generate access token", nil)
+	}
+	plaintext = OAuthTokenPrefix + plaintext
+
+	token := &models.OAuthAccessToken{
+		HubClientID:    secret.HubClientID,
+		ClientSecretID: secret.ID,
+		TokenHash:      hashSecret(plaintext),
+		Scopes:         scopes,
+		ExpiresAt:      time.Now().Add(s.tokenTTL),
+	}
+	if err := s.tokens.Create(tenant.WithHubClientID(ctx, secret.HubClientID), token); err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+
+	return &dto.OAuthTokenDTO{
+		AccessToken: plaintext,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.tokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// Introspect describes a token to the client it was issued to, as in RFC 7662.
+// Tokens of other clients are reported inactive.
+func (s *oauthService) Introspect(ctx context.Context, clientID string, clientSecret string, plaintext string) (*dto.OAuthIntrospectionDTO, error) {
+	ctx, span := tracing.Start(ctx, "OAuthService.Introspect")
+	defer span.End()
+
+	secret, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.clientToken(ctx, secret.HubClientID, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || !token.Active(time.Now()) {
+		return &dto.OAuthIntrospectionDTO{Active: false}, nil
+	}
+
+	return &dto.OAuthIntrospectionDTO{
+		Active:    true,
+		Scope:     strings.Join(token.Scopes, " "),
+		ClientID:  clientID,
+		TokenType: "Bearer",
+		Exp:       token.ExpiresAt.Unix(),
+		Iat:       token.CreatedAt.Unix(),
+	}, nil
+}
+
+// RevokeToken revokes a token of the client, as in RFC 7009.
+// Unknown tokens and tokens of other clients are ignored.
+func (s *oauthService) RevokeToken(ctx context.Context, clientID string, clientSecret string, plaintext string) error {
+	ctx, span := tracing.Start(ctx, "OAuthService.RevokeToken")
+	defer span.End()
+
+	secret, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.clientToken(ctx, secret.HubClientID, plaintext)
+	if err != nil || token == nil || token.RevokedAt != nil {
+		return err
+	}
+	return utils.HandleDBError(s.tokens.Revoke(tenant.WithHubClientID(ctx, secret.HubClientID), token, time.Now()))
+}
+
+// Authenticate returns the active access token matching the plaintext value of an active hub client.
+// The token is looked up across tenants, since its hub client is not known yet.
+func (s *oauthService) Authenticate(ctx context.Context, plaintext string) (*models.OAuthAccessToken, error) {
+	ctx, span := tracing.Start(ctx, "OAuthService.Authenticate")
+	defer span.End()
+
+	if !IsOAuthToken(plaintext) {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	token, err := s.tokens.GetByHash(tenant.WithoutScope(ctx), hashSecret(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+	if !token.Active(time.Now()) {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	hubClient, err := s.hubClients.GetByID(token.HubClientID)
+	if err != nil || !hubClient.Active {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	return token, nil
+}
+
+// DeleteExpired removes every expired access token
+func (s *oauthService) DeleteExpired() (int64, error) {
+	deleted, err := s.tokens.DeleteExpired(tenant.WithoutScope(context.Background()), time.Now())
+	return deleted, utils.HandleDBError(err)
+}
+
+// authenticateClient returns the usable secret matching the client credentials.
+// Every failure is reported as invalid_client, so the response does not reveal which part was wrong.
+func (s *oauthService) authenticateClient(ctx context.Context, clientID string, plaintext string) (*models.OAuthClientSecret, error) {
+	invalid := OAuthError(http.StatusUnauthorized, OAuthInvalidClient, "Client authentication failed")
+
+	prefix, ok := secretLookupPrefix(OAuthClientSecretPrefix, plaintext)
+	if clientID == "" || !ok {
+		return nil, invalid
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	secret, err := s.secrets.GetByPrefix(ctx, prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(plaintext)), []byte(secret.SecretHash)) != 1 {
+		return nil, invalid
+	}
+
+	now := time.Now()
+	if !secret.Usable(now) {
+		return nil, invalid
+	}
+
+	hubClient, err := s.hubClients.GetByID(secret.HubClientID)
+	if err != nil || !hubClient.Active || hubClient.ExternalID != clientID {
+		return nil, invalid
+	}
+
+	if secret.LastUsedAt == nil || now.Sub(*secret.LastUsedAt) >= lastUsedResolution {
+		if err := s.secrets.TouchLastUsed(ctx, secret, now); err != nil {
+			return nil, utils.HandleDBError(err)
+		}
+	}
+
+	return secret, nil
+}
+
+// clientToken returns the token matching the plaintext value when it belongs to the hub client, or nil
+func (s *oauthService) clientToken(ctx context.Context, hubClientID uint, plaintext string) (*models.OAuthAccessToken, error) {
+	if !IsOAuthToken(plaintext) {
+		return nil, nil
+	}
+
+	token, err := s.tokens.GetByHash(tenant.WithHubClientID(ctx, hubClientID), hashSecret(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+	return token, nil
+}
+
+// checkHubClient returns a 404 when the hub client does not exist
+func (s *oauthService) checkHubClient(hubClientID uint) error {
+	_, err := s.hubClients.GetByID(hubClientID)
+	return utils.HandleDBError(err)
+}
@@ -0,0 +1,269 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockOAuthClientSecretRepository
+// ---------------------------
+
+type MockOAuthClientSecretRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientSecretRepository) ListByHubClient(ctx context.Context, hubClientID uint) ([]models.OAuthClientSecret, error) {
+	args := m.Called(hubClientID)
+	return args.Get(0).([]models.OAuthClientSecret), args.Error(1)
+}
+
+func (m *MockOAuthClientSecretRepository) GetByHubClient(ctx context.Context, hubClientID uint, id uint) (*models.OAuthClientSecret, error) {
+	args := m.Called(hubClientID, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthClientSecret), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthClientSecretRepository) GetByPrefix(ctx context.Context, prefix string) (*models.OAuthClientSecret, error) {
+	args := m.Called(prefix)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthClientSecret), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthClientSecretRepository) Create(ctx context.Context, secret *models.OAuthClientSecret) error {
+	args := m.Called(secret)
+	return args.Error(0)
+}
+
+func (m *MockOAuthClientSecretRepository) Revoke(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error {
+	args := m.Called(secret, at)
+	return args.Error(0)
+}
+
+func (m *MockOAuthClientSecretRepository) TouchLastUsed(ctx context.Context, secret *models.OAuthClientSecret, at time.Time) error {
+	args := m.Called(secret, at)
+	return args.Error(0)
+}
+
+// ---------------------------
+// MockOAuthAccessTokenRepository
+// ---------------------------
+
+type MockOAuthAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthAccessTokenRepository) Create(ctx context.Context, token *models.OAuthAccessToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAccessTokenRepository) GetByHash(ctx context.Context, hash string) (*models.OAuthAccessToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.OAuthAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthAccessTokenRepository) Revoke(ctx context.Context, token *models.OAuthAccessToken, at time.Time) error {
+	args := m.Called(token, at)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAccessTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+const testClientSecret = "gmcs_0123456789ab_0123456789abcdef0123456789abcdef"
+
+type oauthFixture struct {
+	secrets    *MockOAuthClientSecretRepository
+	tokens     *MockOAuthAccessTokenRepository
+	hubClients *MockHubClientRepository
+	service    services.OAuthService
+	secret     *models.OAuthClientSecret
+}
+
+// newOAuthFixture registers testClientSecret for the active hub client 4, whose client_id is "partner"
+func newOAuthFixture(scopes ...string) *oauthFixture {
+	f := &oauthFixture{
+		secrets:    new(MockOAuthClientSecretRepository),
+		tokens:     new(MockOAuthAccessTokenRepository),
+		hubClients: new(MockHubClientRepository),
+	}
+	f.service = services.NewOAuthService(f.secrets, f.tokens, f.hubClients, scopeAuthorization(4, "roles:read", "roles:create", "roles:update", "roles:delete"), 15*time.Minute)
+
+	f.secret = &models.OAuthClientSecret{
+		BaseID:      models.BaseID{ID: 2},
+		HubClientID: 4,
+		Prefix:      "gmcs_0123456789ab",
+		SecretHash:  sha256Hex(testClientSecret),
+		Scopes:      scopes,
+	}
+	f.secrets.On("GetByPrefix", "gmcs_0123456789ab").Return(f.secret, nil)
+	f.secrets.On("TouchLastUsed", f.secret, mock.AnythingOfType("time.Time")).Return(nil)
+	f.hubClients.On("GetByID", uint(4)).Return(&models.HubClient{
+		BaseID:     models.BaseID{ID: 4},
+		Active:     true,
+		ExternalID: "partner",
+	}, nil)
+	return f
+}
+
+func assertOAuthError(t *testing.T, err error, status int, code string) {
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, status, apiErr.Status)
+	assert.Equal(t, code, apiErr.Code)
+}
+
+func TestOAuthIssueToken(t *testing.T) {
+	f := newOAuthFixture("roles:write", "hub_clients:read")
+	f.tokens.On("Create", mock.AnythingOfType("*models.OAuthAccessToken")).Return(nil)
+
+	issued, err := f.service.IssueToken(context.Background(), "partner", testClientSecret, "roles:read")
+	require.NoError(t, err)
+	assert.True(t, services.IsOAuthToken(issued.AccessToken))
+	assert.Equal(t, "Bearer", issued.TokenType)
+	assert.Equal(t, int64(900), issued.ExpiresIn)
+	assert.Equal(t, "roles:read", issued.Scope)
+
+	token := f.tokens.Calls[0].Arguments.Get(0).(*models.OAuthAccessToken)
+	assert.Equal(t, uint(4), token.HubClientID)
+	assert.Equal(t, uint(2), token.ClientSecretID)
+	assert.Equal(t, sha256Hex(issued.AccessToken), token.TokenHash)
+	assert.Equal(t, []string{"roles:read"}, token.Scopes)
+}
+
+func TestOAuthIssueToken_DefaultsToSecretScopes(t *testing.T) {
+	f := newOAuthFixture("roles:write", "hub_clients:read")
+	f.tokens.On("Create", mock.AnythingOfType("*models.OAuthAccessToken")).Return(nil)
+
+	issued, err := f.service.IssueToken(context.Background(), "partner", testClientSecret, "")
+	require.NoError(t, err)
+	assert.Equal(t, "roles:write hub_clients:read", issued.Scope)
+}
+
+func TestOAuthIssueToken_InvalidScope(t *testing.T) {
+	f := newOAuthFixture("roles:read")
+
+	_, err := f.service.IssueToken(context.Background(), "partner", testClientSecret, "roles:write")
+	assertOAuthError(t, err, 400, services.OAuthInvalidScope)
+
+	f.tokens.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestOAuthIssueToken_InvalidClient(t *testing.T) {
+	f := newOAuthFixture("roles:read")
+
+	cases := map[string][2]string{
+		"other client id": {"someone-else", testClientSecret},
+		"wrong secret":    {"partner", strings.TrimSuffix(testClientSecret, "f") + "0"},
+		"malformed":       {"partner", "not-a-secret"},
+		"missing id":      {"", testClientSecret},
+	}
+	for name, credentials := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := f.service.IssueToken(context.Background(), credentials[0], credentials[1], "")
+			assertOAuthError(t, err, 401, services.OAuthInvalidClient)
+		})
+	}
+}
+
+func TestOAuthIssueToken_RevokedSecret(t *testing.T) {
+	f := newOAuthFixture("roles:read")
+	revokedAt := time.Now()
+	f.secret.RevokedAt = &revokedAt
+
+	_, err := f.service.IssueToken(context.Background(), "partner", testClientSecret, "")
+	assertOAuthError(t, err, 401, services.OAuthInvalidClient)
+}
+
+func TestOAuthIntrospect(t *testing.T) {
+	f := newOAuthFixture("roles:read")
+	expiresAt := time.Now().Add(time.Minute)
+	f.tokens.On("GetByHash", sha256Hex("gmo_active")).Return(&models.OAuthAccessToken{
+		HubClientID: 4,
+		Scopes:      []string{"roles:read"},
+		ExpiresAt:   expiresAt,
+	}, nil)
+	f.tokens.On("GetByHash", sha256Hex("gmo_unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	introspection, err := f.service.Introspect(context.Background(), "partner", testClientSecret, "gmo_active")
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "roles:read", introspection.Scope)
+	assert.Equal(t, "partner", introspection.ClientID)
+	assert.Equal(t, expiresAt.Unix(), introspection.Exp)
+
+	introspection, err = f.service.Introspect(context.Background(), "partner", testClientSecret, "gmo_unknown")
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
+
+func TestOAuthRevokeToken_IgnoresUnknownTokens(t *testing.T) {
+	f := newOAuthFixture("roles:read")
+	f.tokens.On("GetByHash", sha256Hex("gmo_unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, f.service.RevokeToken(context.Background(), "partner", testClientSecret, "gmo_unknown"))
+	assert.NoError(t, f.service.RevokeToken(context.Background(), "partner", testClientSecret, "not-a-token"))
+
+	f.tokens.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestOAuthAuthenticate(t *testing.T) {
+	f := newOAuthFixture()
+	active := &models.OAuthAccessToken{HubClientID: 4, ExpiresAt: time.Now().Add(time.Minute)}
+	f.tokens.On("GetByHash", sha256Hex("gmo_active")).Return(active, nil)
+	f.tokens.On("GetByHash", sha256Hex("gmo_expired")).Return(&models.OAuthAccessToken{HubClientID: 4, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+	token, err := f.service.Authenticate(context.Background(), "gmo_active")
+	require.NoError(t, err)
+	assert.Equal(t, active, token)
+
+	_, err = f.service.Authenticate(context.Background(), "gmo_expired")
+	assertUnauthorized(t, err)
+}
+
+func TestOAuthCreateSecret_ScopesBeyondCaller(t *testing.T) {
+	f := newOAuthFixture()
+	f.secrets.On("Create", mock.AnythingOfType("*models.OAuthClientSecret")).Return(nil)
+
+	for _, scopes := range [][]string{{"*"}, {"users:read"}, {"roles:write", "users:write"}} {
+		_, _, err := f.service.CreateSecret(callerContext(), 4, &dto.CreateOAuthClientSecretDTO{Name: "Partner", Scopes: scopes})
+		assertForbidden(t, err)
+	}
+	f.secrets.AssertNotCalled(t, "Create", mock.Anything)
+
+	secret, plaintext, err := f.service.CreateSecret(callerContext(), 4, &dto.CreateOAuthClientSecretDTO{Name: "Partner", Scopes: []string{"roles:write"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"roles:write"}, secret.Scopes)
+	assert.NotEmpty(t, plaintext)
+
+	// Holders of a super role can grant every scope
+	_, _, err = f.service.CreateSecret(services.WithSuper(callerContext()), 4, &dto.CreateOAuthClientSecretDTO{Name: "Partner", Scopes: []string{"*"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/utils"
+
+	"go.uber.org/zap"
+)
+
+// PasswordResetNotifier delivers a password reset token to its user, e.g. by email
+type PasswordResetNotifier interface {
+	NotifyPasswordReset(ctx context.Context, user *models.User, token string, expiresAt time.Time) error
+}
+
+// LogPasswordResetNotifier is used when no notifier is configured. It logs that a reset was
+// requested, never the token, so the token is not delivered at all.
+type LogPasswordResetNotifier struct {
+	Logger *zap.Logger
+}
+
+// NotifyPasswordReset logs the request of the user
+func (n LogPasswordResetNotifier) NotifyPasswordReset(ctx context.Context, user *models.User, _ string, expiresAt time.Time) error {
+	utils.ContextLogger(ctx, n.Logger).Warn("Password reset requested but no notifier is configured, set SMTP_HOST to deliver it",
+		zap.Uint("user_id", user.ID),
+		zap.Time("expires_at", expiresAt),
+	)
+	return nil
+}
+
+// SMTPPasswordResetNotifier emails the reset link to the user
+type SMTPPasswordResetNotifier struct {
+	// Addr is the host:port of the SMTP server, STARTTLS is used when it supports it
+	Addr string
+	// Auth authenticates to the server, nil sends anonymously
+	Auth smtp.Auth
+	From string
+	// ResetURL is the page of the frontend resetting passwords, the token is added as its token query parameter
+	ResetURL string
+	// Send sends the message, smtp.SendMail when nil
+	Send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPPasswordResetNotifier creates an SMTPPasswordResetNotifier using PLAIN authentication
+// when a username is given
+func NewSMTPPasswordResetNotifier(host string, port int, username, password, from, resetURL string) *SMTPPasswordResetNotifier {
+	notifier := &SMTPPasswordResetNotifier{
+		Addr:     net.JoinHostPort(host, fmt.Sprint(port)),
+		From:     from,
+		ResetURL: resetURL,
+	}
+	if username != "" {
+		notifier.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return notifier
+}
+
+// NotifyPasswordReset emails the reset link of the token to the user
+func (n *SMTPPasswordResetNotifier) NotifyPasswordReset(ctx context.Context, user *models.User, token string, expiresAt time.Time) error {
+	// Addresses end up in headers, line breaks would inject new ones
+	if strings.ContainsAny(user.Email+n.From, "\r\n") {
+		return errors.New("invalid email address")
+	}
+
+	link, err := url.Parse(n.ResetURL)
+	if err != nil {
+		return fmt.Errorf("invalid password reset URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	msg := strings.Join([]string{
+		"From: " + n.From,
+		"To: " + user.Email,
+		"Subject: Reset your password",
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		"A password reset was requested for your account. Open the link below to choose a new password:",
+		"",
+		link.String(),
+		"",
+		"The link expires at " + expiresAt.UTC().Format(time.RFC1123) + ". If you did not request it, ignore this email.",
+		"",
+	}, "\r\n")
+
+	send := n.Send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(n.Addr, n.Auth, n.From, []string{user.Email}, []byte(msg))
+}
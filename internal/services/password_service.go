@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const passwordResetTokenBytes = 32
+
+// PasswordService defines the password change and reset flows of users
+type PasswordService interface {
+	Change(ctx context.Context, userID uint, currentPassword string, newPassword string) error
+	RequestReset(ctx context.Context, email string) error
+	Reset(ctx context.Context, token string, newPassword string) error
+}
+
+type passwordService struct {
+	users       repositories.UserRepository
+	tokens      repositories.PasswordResetTokenRepository
+	revocations RevocationService
+	notifier    PasswordResetNotifier
+	resetTTL    time.Duration
+}
+
+func NewPasswordService(users repositories.UserRepository, tokens repositories.PasswordResetTokenRepository, revocations RevocationService, notifier PasswordResetNotifier, resetTTL time.Duration) PasswordService {
+	return &passwordService{users: users, tokens: tokens, revocations: revocations, notifier: notifier, resetTTL: resetTTL}
+}
+
+// Change replaces the password of a user who knows the current one and revokes every token
+// issued to the user so far, the one of the request included.
+// The user is identified by its token, so the lookup is not scoped to a tenant.
+func (s *passwordService) Change(ctx context.Context, userID uint, currentPassword string, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.Change")
+	defer span.End()
+
+	ctx = tenant.WithoutScope(ctx)
+	user, err := s.users.WithContext(ctx).GetByID(userID)
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+
+	if !utils.CheckPassword(user.Password, currentPassword) {
+		return exceptions.BadRequest("Current password is incorrect", map[string]interface{}{"field": "current_password"})
+	}
+
+	hash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return exceptions.InternalServerError("Failed to hash password", nil)
+	}
+	if err := s.users.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return utils.HandleDBError(err)
+	}
+	return s.revocations.RevokeUser(ctx, user.ID)
+}
+
+// RequestReset creates a reset token for the active user with the email and hands it to the notifier.
+// Unknown emails succeed silently and the token is issued in the background, so neither the result
+// nor the response time reveals which accounts exist. Failures to issue it are logged.
+func (s *passwordService) RequestReset(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.RequestReset")
+	defer span.End()
+
+	ctx = tenant.WithoutScope(ctx)
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+
+	// The token outlives the request, which keeps its values such as the trace but not its deadline
+	go s.issueReset(context.WithoutCancel(ctx), user)
+	return nil
+}
+
+// issueReset stores a new reset token of the user and hands it to the notifier
+func (s *passwordService) issueReset(ctx context.Context, user *models.User) {
+	ctx, span := tracing.Start(ctx, "PasswordService.issueReset")
+	defer span.End()
+
+	log := utils.ContextLogger(ctx, utils.Logger.Named("password_reset")).With(zap.Uint("user_id", user.ID))
+
+	plaintext, err := randomHex(passwordResetTokenBytes)
+	if err != nil {
+		log.Error("Failed to generate reset token", zap.Error(err))
+		return
+	}
+
+	token := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashSecret(plaintext),
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}
+	if err := s.tokens.Create(ctx, token); err != nil {
+		log.Error("Failed to store reset token", zap.Error(err))
+		return
+	}
+
+	if err := s.notifier.NotifyPasswordReset(ctx, user, plaintext, token.ExpiresAt); err != nil {
+		log.Error("Failed to send reset token", zap.Error(err))
+	}
+}
+
+// Reset sets a new password with a reset token, which can only be used once, and revokes every
+// token issued to the user so far
+func (s *passwordService) Reset(ctx context.Context, plaintext string, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.Reset")
+	defer span.End()
+
+	ctx = tenant.WithoutScope(ctx)
+	invalid := exceptions.BadRequest("Invalid or expired reset token", map[string]interface{}{"field": "token"})
+
+	token, err := s.tokens.GetByHash(ctx, hashSecret(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return invalid
+	}
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+
+	now := time.Now()
+	if !token.Usable(now) {
+		return invalid
+	}
+
+	hash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return exceptions.InternalServerError("Failed to hash password", nil)
+	}
+
+	err = s.tokens.Consume(ctx, token, hash, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return invalid
+	}
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	return s.revocations.RevokeUser(ctx, token.UserID)
+}
@@ -0,0 +1,253 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/smtp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+)
+
+// ---------------------------
+// MockPasswordResetTokenRepository
+// ---------------------------
+
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) GetByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) Consume(ctx context.Context, token *models.PasswordResetToken, passwordHash string, at time.Time) error {
+	args := m.Called(token, passwordHash, at)
+	return args.Error(0)
+}
+
+// recordingNotifier keeps the last token it was asked to deliver, which happens in the background
+type recordingNotifier struct {
+	mu    sync.Mutex
+	user  *models.User
+	token string
+}
+
+func (n *recordingNotifier) NotifyPasswordReset(_ context.Context, user *models.User, token string, _ time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.user, n.token = user, token
+	return nil
+}
+
+func (n *recordingNotifier) last() (*models.User, string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.user, n.token
+}
+
+// blockingNotifier delivers tokens once released
+type blockingNotifier struct {
+	release chan struct{}
+}
+
+func (n blockingNotifier) NotifyPasswordReset(context.Context, *models.User, string, time.Time) error {
+	<-n.release
+	return nil
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func assertBadRequest(t *testing.T, err error) {
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 400, apiErr.Status)
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockRevocations := new(MockRevocationService)
+	service := services.NewPasswordService(mockUsers, new(MockPasswordResetTokenRepository), mockRevocations, &recordingNotifier{}, time.Hour)
+
+	user := newTestUser(t)
+	mockUsers.On("GetByID", user.ID).Return(user, nil)
+	mockUsers.On("UpdatePassword", user.ID, mock.MatchedBy(func(hash string) bool {
+		return utils.CheckPassword(hash, "new-secret")
+	})).Return(nil)
+
+	mockRevocations.On("RevokeUser", user.ID).Return(nil)
+
+	assert.NoError(t, service.Change(context.Background(), user.ID, "secret", "new-secret"))
+
+	mockUsers.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockRevocations := new(MockRevocationService)
+	service := services.NewPasswordService(mockUsers, new(MockPasswordResetTokenRepository), mockRevocations, &recordingNotifier{}, time.Hour)
+
+	user := newTestUser(t)
+	mockUsers.On("GetByID", user.ID).Return(user, nil)
+
+	err := service.Change(context.Background(), user.ID, "wrong", "new-secret")
+	assertBadRequest(t, err)
+
+	mockUsers.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	mockRevocations.AssertNotCalled(t, "RevokeUser", mock.Anything)
+}
+
+func TestRequestReset_IssuesToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockPasswordResetTokenRepository)
+	notifier := &recordingNotifier{}
+	service := services.NewPasswordService(mockUsers, mockTokens, new(MockRevocationService), notifier, time.Hour)
+
+	user := newTestUser(t)
+	mockUsers.On("GetByEmail", user.Email).Return(user, nil)
+	mockTokens.On("Create", mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+
+	require.NoError(t, service.RequestReset(context.Background(), user.Email))
+	require.Eventually(t, func() bool {
+		_, token := notifier.last()
+		return token != ""
+	}, time.Second, time.Millisecond)
+
+	notified, plaintext := notifier.last()
+	assert.Equal(t, user, notified)
+	assert.Len(t, plaintext, 64)
+
+	token := mockTokens.Calls[0].Arguments.Get(0).(*models.PasswordResetToken)
+	assert.Equal(t, user.ID, token.UserID)
+	assert.Equal(t, sha256Hex(plaintext), token.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func TestRequestReset_DoesNotWaitForDelivery(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockPasswordResetTokenRepository)
+	notifier := blockingNotifier{release: make(chan struct{})}
+	defer close(notifier.release)
+	service := services.NewPasswordService(mockUsers, mockTokens, new(MockRevocationService), notifier, time.Hour)
+
+	user := newTestUser(t)
+	mockUsers.On("GetByEmail", user.Email).Return(user, nil)
+	mockTokens.On("Create", mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+
+	// Known emails answer as fast as unknown ones, whatever the delivery takes
+	assert.NoError(t, service.RequestReset(context.Background(), user.Email))
+}
+
+func TestRequestReset_UnknownEmail(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockPasswordResetTokenRepository)
+	notifier := &recordingNotifier{}
+	service := services.NewPasswordService(mockUsers, mockTokens, new(MockRevocationService), notifier, time.Hour)
+
+	mockUsers.On("GetByEmail", "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, service.RequestReset(context.Background(), "nobody@example.com"))
+	_, token := notifier.last()
+	assert.Empty(t, token)
+	mockTokens.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	mockTokens := new(MockPasswordResetTokenRepository)
+	mockRevocations := new(MockRevocationService)
+	service := services.NewPasswordService(new(MockUserRepository), mockTokens, mockRevocations, &recordingNotifier{}, time.Hour)
+
+	token := &models.PasswordResetToken{UserID: 7, ExpiresAt: time.Now().Add(time.Hour)}
+	mockTokens.On("GetByHash", sha256Hex("plaintext")).Return(token, nil)
+	mockTokens.On("Consume", token, mock.MatchedBy(func(hash string) bool {
+		return utils.CheckPassword(hash, "new-secret")
+	}), mock.AnythingOfType("time.Time")).Return(nil)
+	mockRevocations.On("RevokeUser", uint(7)).Return(nil)
+
+	assert.NoError(t, service.Reset(context.Background(), "plaintext", "new-secret"))
+
+	mockTokens.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	mockTokens := new(MockPasswordResetTokenRepository)
+	mockRevocations := new(MockRevocationService)
+	service := services.NewPasswordService(new(MockUserRepository), mockTokens, mockRevocations, &recordingNotifier{}, time.Hour)
+
+	usedAt := time.Now()
+	mockTokens.On("GetByHash", sha256Hex("unknown")).Return(nil, gorm.ErrRecordNotFound)
+	mockTokens.On("GetByHash", sha256Hex("expired")).Return(&models.PasswordResetToken{ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+	mockTokens.On("GetByHash", sha256Hex("used")).Return(&models.PasswordResetToken{ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+
+	for _, plaintext := range []string{"unknown", "expired", "used"} {
+		assertBadRequest(t, service.Reset(context.Background(), plaintext, "new-secret"))
+	}
+
+	mockTokens.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+	mockRevocations.AssertNotCalled(t, "RevokeUser", mock.Anything)
+}
+
+func TestSMTPPasswordResetNotifier(t *testing.T) {
+	var sent []byte
+	var recipients []string
+	notifier := services.NewSMTPPasswordResetNotifier("smtp.example.com", 587, "", "", "no-reply@example.com", "https://app.example.com/reset?lang=en")
+	notifier.Send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "no-reply@example.com", from)
+		recipients, sent = to, msg
+		return nil
+	}
+
+	user := &models.User{Email: "jane@example.com"}
+	require.NoError(t, notifier.NotifyPasswordReset(context.Background(), user, "abc123", time.Now().Add(time.Hour)))
+	assert.Equal(t, []string{"jane@example.com"}, recipients)
+	assert.Contains(t, string(sent), "To: jane@example.com\r\n")
+	assert.Contains(t, string(sent), "https://app.example.com/reset?lang=en&token=abc123")
+
+	user.Email = "jane@example.com\r\nBcc: eve@example.com"
+	assert.Error(t, notifier.NotifyPasswordReset(context.Background(), user, "abc123", time.Now().Add(time.Hour)))
+}
+
+func TestLogPasswordResetNotifier_DoesNotLogToken(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	notifier := services.LogPasswordResetNotifier{Logger: zap.New(core)}
+
+	require.NoError(t, notifier.NotifyPasswordReset(context.Background(), &models.User{}, "abc123", time.Now()))
+	require.Equal(t, 1, logs.Len())
+	for _, field := range logs.All()[0].Context {
+		assert.NotEqual(t, "abc123", field.String)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tenant"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// RevocationService defines the revocation of user tokens before they expire, one at a time
+// by their jti or all at once for a user or a hub client
+type RevocationService interface {
+	RevokeToken(ctx context.Context, claims *Claims) error
+	RevokeUser(ctx context.Context, userID uint) error
+	RevokeHubClient(ctx context.Context, hubClientID uint) error
+	Check(ctx context.Context, claims *Claims) error
+	DeleteExpired() (int64, error)
+}
+
+type revocationService struct {
+	repo       repositories.TokenRevocationRepository
+	users      repositories.UserRepository
+	hubClients repositories.HubClientRepository
+	sessionTTL time.Duration
+}
+
+// NewRevocationService creates a RevocationService. The sessionTTL is the longest lifetime of
+// a user token, after which a revocation of every token of a user or hub client can be dropped.
+func NewRevocationService(repo repositories.TokenRevocationRepository, users repositories.UserRepository, hubClients repositories.HubClientRepository, sessionTTL time.Duration) RevocationService {
+	return &revocationService{repo: repo, users: users, hubClients: hubClients, sessionTTL: sessionTTL}
+}
+
+// RevokeToken revokes the token with the claims until it expires
+func (s *revocationService) RevokeToken(ctx context.Context, claims *Claims) error {
+	ctx, span := tracing.Start(ctx, "RevocationService.RevokeToken")
+	defer span.End()
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return exceptions.BadRequest("Token cannot be revoked", nil)
+	}
+
+	return utils.HandleDBError(s.repo.Save(ctx, &models.TokenRevocation{
+		Kind:      models.RevokedToken,
+		Key:       claims.ID,
+		RevokedAt: time.Now(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}))
+}
+
+// RevokeUser revokes every token issued so far to a user of the tenant
+func (s *revocationService) RevokeUser(ctx context.Context, userID uint) error {
+	ctx, span := tracing.Start(ctx, "RevocationService.RevokeUser")
+	defer span.End()
+
+	if _, err := s.users.WithContext(ctx).GetByID(userID); err != nil {
+		return utils.HandleDBError(err)
+	}
+	return s.revokeAll(ctx, models.RevokedUser, userID)
+}
+
+// RevokeHubClient revokes every user token issued so far for a hub client. Callers acting for
+// another hub client get a 404 unless they hold a super role.
+// API keys and OAuth2 tokens have their own revocation endpoints.
+func (s *revocationService) RevokeHubClient(ctx context.Context, hubClientID uint) error {
+	ctx, span := tracing.Start(ctx, "RevocationService.RevokeHubClient")
+	defer span.End()
+
+	if current, ok := tenant.HubClientID(ctx); ok && current != hubClientID && !IsSuper(ctx) {
+		return utils.HandleDBError(gorm.ErrRecordNotFound)
+	}
+	if _, err := s.hubClients.GetByID(hubClientID); err != nil {
+		return utils.HandleDBError(err)
+	}
+	return s.revokeAll(ctx, models.RevokedHubClient, hubClientID)
+}
+
+// Check returns a 401 when the token with the claims has been revoked
+func (s *revocationService) Check(ctx context.Context, claims *Claims) error {
+	ctx, span := tracing.Start(ctx, "RevocationService.Check")
+	defer span.End()
+
+	subjects := map[string]string{models.RevokedUser: claims.Subject}
+	if claims.HubClientID != nil {
+		subjects[models.RevokedHubClient] = strconv.FormatUint(uint64(*claims.HubClientID), 10)
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	revoked, err := s.repo.Revoked(ctx, claims.ID, subjects, issuedAt, time.Now())
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	if revoked {
+		return exceptions.Unauthorized("Token has been revoked", nil)
+	}
+	return nil
+}
+
+// DeleteExpired removes the revocations whose tokens have all expired
+func (s *revocationService) DeleteExpired() (int64, error) {
+	deleted, err := s.repo.DeleteExpired(context.Background(), time.Now())
+	return deleted, utils.HandleDBError(err)
+}
+
+// revokeAll revokes the tokens of a subject issued up to now. Token issue times are rounded down
+// to the second, so tokens issued in the same second are revoked too.
+func (s *revocationService) revokeAll(ctx context.Context, kind string, id uint) error {
+	now := time.Now()
+	return utils.HandleDBError(s.repo.Save(ctx, &models.TokenRevocation{
+		Kind:      kind,
+		Key:       strconv.FormatUint(uint64(id), 10),
+		RevokedAt: now,
+		ExpiresAt: now.Add(s.sessionTTL),
+	}))
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+	"go-modules-api/internal/tenant"
+)
+
+// ---------------------------
+// MockTokenRevocationRepository
+// ---------------------------
+
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) Save(ctx context.Context, revocation *models.TokenRevocation) error {
+	args := m.Called(revocation)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) Revoked(ctx context.Context, tokenID string, subjects map[string]string, issuedAt time.Time, now time.Time) (bool, error) {
+	args := m.Called(tokenID, subjects, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func TestRevokeToken_ExpiresWithToken(t *testing.T) {
+	mockRepo := new(MockTokenRevocationRepository)
+	service := services.NewRevocationService(mockRepo, new(MockUserRepository), new(MockHubClientRepository), time.Hour)
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	mockRepo.On("Save", mock.AnythingOfType("*models.TokenRevocation")).Return(nil)
+
+	err := service.RevokeToken(context.Background(), &services.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "jti",
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}})
+	require.NoError(t, err)
+
+	revocation := mockRepo.Calls[0].Arguments.Get(0).(*models.TokenRevocation)
+	assert.Equal(t, models.RevokedToken, revocation.Kind)
+	assert.Equal(t, "jti", revocation.Key)
+	assert.Equal(t, expiresAt, revocation.ExpiresAt)
+}
+
+func TestRevokeUser(t *testing.T) {
+	mockRepo := new(MockTokenRevocationRepository)
+	mockUsers := new(MockUserRepository)
+	service := services.NewRevocationService(mockRepo, mockUsers, new(MockHubClientRepository), time.Hour)
+
+	mockUsers.On("GetByID", uint(7)).Return(&models.User{BaseID: models.BaseID{ID: 7}}, nil)
+	mockRepo.On("Save", mock.AnythingOfType("*models.TokenRevocation")).Return(nil)
+
+	before := time.Now()
+	require.NoError(t, service.RevokeUser(context.Background(), 7))
+
+	revocation := mockRepo.Calls[0].Arguments.Get(0).(*models.TokenRevocation)
+	assert.Equal(t, models.RevokedUser, revocation.Kind)
+	assert.Equal(t, "7", revocation.Key)
+	assert.False(t, revocation.RevokedAt.Before(before))
+	assert.Equal(t, revocation.RevokedAt.Add(time.Hour), revocation.ExpiresAt)
+}
+
+func TestRevokeHubClient_NotFound(t *testing.T) {
+	mockRepo := new(MockTokenRevocationRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewRevocationService(mockRepo, new(MockUserRepository), mockHubClients, time.Hour)
+
+	mockHubClients.On("GetByID", uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.RevokeHubClient(context.Background(), 4)
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestRevokeHubClient_OtherTenant(t *testing.T) {
+	mockRepo := new(MockTokenRevocationRepository)
+	mockHubClients := new(MockHubClientRepository)
+	service := services.NewRevocationService(mockRepo, new(MockUserRepository), mockHubClients, time.Hour)
+
+	ctx := tenant.WithHubClientID(context.Background(), 3)
+	err := service.RevokeHubClient(ctx, 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Record not found")
+	mockHubClients.AssertNotCalled(t, "GetByID", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+
+	mockHubClients.On("GetByID", uint(4)).Return(&models.HubClient{}, nil)
+	mockRepo.On("Save", mock.Anything).Return(nil)
+	assert.NoError(t, service.RevokeHubClient(services.WithSuper(ctx), 4))
+}
+
+func TestCheck(t *testing.T) {
+	mockRepo := new(MockTokenRevocationRepository)
+	service := services.NewRevocationService(mockRepo, new(MockUserRepository), new(MockHubClientRepository), time.Hour)
+
+	hubClientID := uint(3)
+	issuedAt := time.Now().Truncate(time.Second)
+	claims := func(id string) *services.Claims {
+		return &services.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{ID: id, Subject: "7", IssuedAt: jwt.NewNumericDate(issuedAt)},
+			HubClientID:      &hubClientID,
+		}
+	}
+	subjects := map[string]string{models.RevokedUser: "7", models.RevokedHubClient: "3"}
+	mockRepo.On("Revoked", "live", subjects, issuedAt).Return(false, nil)
+	mockRepo.On("Revoked", "revoked", subjects, issuedAt).Return(true, nil)
+
+	assert.NoError(t, service.Check(context.Background(), claims("live")))
+	assertUnauthorized(t, service.Check(context.Background(), claims("revoked")))
+}
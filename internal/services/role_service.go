@@ -1,64 +1,68 @@
 package services
 
 import (
-	"go-modules-api/internal/dto"
+	"context"
+
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/models"
 	"go-modules-api/internal/repositories"
-	"go-modules-api/utils"
 )
 
 type RoleService interface {
-	PaginateRoles(params dto.PaginatedRoleDTO) ([]models.Role, int64, error)
-	ListRoles(search string, active *bool, sortField string, sortOrder string) ([]models.Role, error)
-	GetRoleByID(id uint) (*models.Role, error)
-	CreateRole(role *models.Role) error
-	UpdateRole(role *models.Role) error
-	DeleteRole(id uint) error
-	SoftDeleteRole(role *models.Role) error
+	BaseServiceInterface[models.Role]
 }
 
+// roleService reserves every change to roles to holders of a super role: roles are shared by
+// every hub client, so no tenant may rename, deactivate or delete the roles of another
 type roleService struct {
-	repo repositories.RoleRepository
+	*BaseService[models.Role]
 }
 
 func NewRoleService(repo repositories.RoleRepository) RoleService {
-	return &roleService{repo: repo}
-}
-
-func (s *roleService) PaginateRoles(params dto.PaginatedRoleDTO) ([]models.Role, int64, error) {
-	roles, total, err := s.repo.Pagination(
-		params.Search,
-		params.Active,
-		params.SortField,
-		params.SortOrder,
-		params.Page,
-		params.PageSize,
-	)
-	return roles, total, utils.HandleDBError(err)
+	return &roleService{BaseService: NewBaseService[models.Role](repo)}
 }
 
-func (s *roleService) ListRoles(search string, active *bool, sortField string, sortOrder string) ([]models.Role, error) {
-	roles, err := s.repo.GetAll(search, active, sortField, sortOrder)
-	return roles, utils.HandleDBError(err)
+// WithContext returns a copy of the service bound to the request context
+func (s *roleService) WithContext(ctx context.Context) BaseServiceInterface[models.Role] {
+	return &roleService{BaseService: s.BaseService.withContext(ctx)}
 }
 
-func (s *roleService) GetRoleByID(id uint) (*models.Role, error) {
-	role, err := s.repo.GetByID(id)
-	return role, utils.HandleDBError(err)
+// Create creates a role
+func (s *roleService) Create(role *models.Role) error {
+	if err := s.requireSuper(); err != nil {
+		return err
+	}
+	return s.BaseService.Create(role)
 }
 
-func (s *roleService) CreateRole(role *models.Role) error {
-	return utils.HandleDBError(s.repo.Create(role))
+// Update updates a role
+func (s *roleService) Update(role *models.Role) error {
+	if err := s.requireSuper(); err != nil {
+		return err
+	}
+	return s.BaseService.Update(role)
 }
 
-func (s *roleService) UpdateRole(role *models.Role) error {
-	return utils.HandleDBError(s.repo.Update(role))
+// Delete removes a role
+func (s *roleService) Delete(id uint) error {
+	if err := s.requireSuper(); err != nil {
+		return err
+	}
+	return s.BaseService.Delete(id)
 }
 
-func (s *roleService) DeleteRole(id uint) error {
-	return utils.HandleDBError(s.repo.Delete(id))
+// SoftDelete marks a role as deleted
+func (s *roleService) SoftDelete(role *models.Role) error {
+	if err := s.requireSuper(); err != nil {
+		return err
+	}
+	return s.BaseService.SoftDelete(role)
 }
 
-func (s *roleService) SoftDeleteRole(role *models.Role) error {
-	return utils.HandleDBError(s.repo.SoftDelete(role))
+// requireSuper rejects the changes of callers without a super role
+func (s *roleService) requireSuper() error {
+	if !IsSuper(s.ctx) {
+		return exceptions.Forbidden("Roles are shared by every hub client, only holders of a super role can change them", nil)
+	}
+	return nil
 }
@@ -1,16 +1,22 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"go-modules-api/internal/dto"
+	"go-modules-api/internal/exceptions"
 	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
 	"go-modules-api/internal/services"
+	"go-modules-api/internal/tenant"
 	"go-modules-api/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ---------------------------
@@ -21,6 +27,10 @@ type MockRoleRepository struct {
 	mock.Mock
 }
 
+func (m *MockRoleRepository) WithContext(ctx context.Context) repositories.ResourceRepositoryInterface[models.Role] {
+	return m
+}
+
 func (m *MockRoleRepository) Pagination(search string, active *bool, sortField string, sortOrder string, page int, pageSize int) ([]models.Role, int64, error) {
 	args := m.Called(search, active, sortField, sortOrder, page, pageSize)
 	return args.Get(0).([]models.Role), args.Get(1).(int64), args.Error(2)
@@ -31,6 +41,11 @@ func (m *MockRoleRepository) GetAll(search string, active *bool, sortField strin
 	return args.Get(0).([]models.Role), args.Error(1)
 }
 
+func (m *MockRoleRepository) LastModified(search string, active *bool) (int64, time.Time, error) {
+	args := m.Called(search, active)
+	return args.Get(0).(int64), args.Get(1).(time.Time), args.Error(2)
+}
+
 func (m *MockRoleRepository) GetByID(id uint) (*models.Role, error) {
 	args := m.Called(id)
 	if args.Get(0) != nil {
@@ -59,6 +74,24 @@ func (m *MockRoleRepository) SoftDelete(role *models.Role) error {
 	return args.Error(0)
 }
 
+func (m *MockRoleRepository) GetByUser(userID uint) ([]models.Role, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByIDs(ids []uint) ([]models.Role, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) EnsureSuper(slug string) (*models.Role, error) {
+	args := m.Called(slug)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // ---------------------------
 // Service Test
 // ---------------------------
@@ -67,7 +100,7 @@ func TestPaginateRoles_Success(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
 	service := services.NewRoleService(mockRepo)
 
-	params := dto.PaginatedRoleDTO{
+	params := dto.PaginatedDTO{
 		Search:    "test",
 		Active:    nil,
 		SortField: "name",
@@ -86,7 +119,7 @@ func TestPaginateRoles_Success(t *testing.T) {
 		On("Pagination", params.Search, params.Active, params.SortField, params.SortOrder, params.Page, params.PageSize).
 		Return(expectedRoles, expectedTotal, nil)
 
-	roles, total, err := service.PaginateRoles(params)
+	roles, total, err := service.Paginate(params)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoles, roles)
 	assert.Equal(t, expectedTotal, total)
@@ -98,7 +131,7 @@ func TestPaginateRoles_Error(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
 	service := services.NewRoleService(mockRepo)
 
-	params := dto.PaginatedRoleDTO{
+	params := dto.PaginatedDTO{
 		Search:    "",
 		Active:    nil,
 		SortField: "name",
@@ -112,7 +145,7 @@ func TestPaginateRoles_Error(t *testing.T) {
 		On("Pagination", params.Search, params.Active, params.SortField, params.SortOrder, params.Page, params.PageSize).
 		Return([]models.Role{}, int64(0), expectedError)
 
-	_, _, err := service.PaginateRoles(params)
+	_, _, err := service.Paginate(params)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -135,7 +168,7 @@ func TestListRoles_Success(t *testing.T) {
 		On("GetAll", search, active, sortField, sortOrder).
 		Return(expectedRoles, nil)
 
-	roles, err := service.ListRoles(search, active, sortField, sortOrder)
+	roles, err := service.List(dto.ListDTO{Search: search, Active: active, SortField: sortField, SortOrder: sortOrder})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoles, roles)
 
@@ -156,7 +189,7 @@ func TestListRoles_Error(t *testing.T) {
 		On("GetAll", search, active, sortField, sortOrder).
 		Return([]models.Role{}, expectedError)
 
-	_, err := service.ListRoles(search, active, sortField, sortOrder)
+	_, err := service.List(dto.ListDTO{Search: search, Active: active, SortField: sortField, SortOrder: sortOrder})
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -173,7 +206,7 @@ func TestGetRoleByID_Success(t *testing.T) {
 		On("GetByID", roleID).
 		Return(expectedRole, nil)
 
-	role, err := service.GetRoleByID(roleID)
+	role, err := service.GetByID(roleID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRole, role)
 
@@ -190,7 +223,7 @@ func TestGetRoleByID_Error(t *testing.T) {
 		On("GetByID", roleID).
 		Return(nil, expectedError)
 
-	role, err := service.GetRoleByID(roleID)
+	role, err := service.GetByID(roleID)
 	assert.Error(t, err)
 	assert.Nil(t, role)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
@@ -200,14 +233,14 @@ func TestGetRoleByID_Error(t *testing.T) {
 
 func TestCreateRole_Success(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	newRole := &models.Role{Name: "New Role"}
 	mockRepo.
 		On("Create", newRole).
 		Return(nil)
 
-	err := service.CreateRole(newRole)
+	err := service.Create(newRole)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -215,7 +248,7 @@ func TestCreateRole_Success(t *testing.T) {
 
 func TestCreateRole_Error(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	newRole := &models.Role{Name: "New Role"}
 	expectedError := errors.New("create error")
@@ -223,7 +256,7 @@ func TestCreateRole_Error(t *testing.T) {
 		On("Create", newRole).
 		Return(expectedError)
 
-	err := service.CreateRole(newRole)
+	err := service.Create(newRole)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -232,14 +265,14 @@ func TestCreateRole_Error(t *testing.T) {
 
 func TestUpdateRole_Success(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	roleToUpdate := &models.Role{Name: "Updated Role"}
 	mockRepo.
 		On("Update", roleToUpdate).
 		Return(nil)
 
-	err := service.UpdateRole(roleToUpdate)
+	err := service.Update(roleToUpdate)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -247,7 +280,7 @@ func TestUpdateRole_Success(t *testing.T) {
 
 func TestUpdateRole_Error(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	roleToUpdate := &models.Role{Name: "Updated Role"}
 	expectedError := errors.New("update error")
@@ -255,7 +288,7 @@ func TestUpdateRole_Error(t *testing.T) {
 		On("Update", roleToUpdate).
 		Return(expectedError)
 
-	err := service.UpdateRole(roleToUpdate)
+	err := service.Update(roleToUpdate)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -264,14 +297,14 @@ func TestUpdateRole_Error(t *testing.T) {
 
 func TestDeleteRole_Success(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	roleID := uint(1)
 	mockRepo.
 		On("Delete", roleID).
 		Return(nil)
 
-	err := service.DeleteRole(roleID)
+	err := service.Delete(roleID)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -279,7 +312,7 @@ func TestDeleteRole_Success(t *testing.T) {
 
 func TestDeleteRole_Error(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	roleID := uint(1)
 	expectedError := errors.New("delete error")
@@ -287,7 +320,7 @@ func TestDeleteRole_Error(t *testing.T) {
 		On("Delete", roleID).
 		Return(expectedError)
 
-	err := service.DeleteRole(roleID)
+	err := service.Delete(roleID)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
@@ -296,14 +329,14 @@ func TestDeleteRole_Error(t *testing.T) {
 
 func TestSoftDeleteRole_Success(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	role := &models.Role{Name: "Role to soft delete"}
 	mockRepo.
 		On("SoftDelete", role).
 		Return(nil)
 
-	err := service.SoftDeleteRole(role)
+	err := service.SoftDelete(role)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -311,7 +344,7 @@ func TestSoftDeleteRole_Success(t *testing.T) {
 
 func TestSoftDeleteRole_Error(t *testing.T) {
 	mockRepo := new(MockRoleRepository)
-	service := services.NewRoleService(mockRepo)
+	service := services.NewRoleService(mockRepo).WithContext(services.WithSuper(context.Background()))
 
 	role := &models.Role{Name: "Role to soft delete"}
 	expectedError := errors.New("soft delete error")
@@ -319,9 +352,35 @@ func TestSoftDeleteRole_Error(t *testing.T) {
 		On("SoftDelete", role).
 		Return(expectedError)
 
-	err := service.SoftDeleteRole(role)
+	err := service.SoftDelete(role)
 	assert.Error(t, err)
 	assert.Equal(t, "[500] internal_server_error: A database error occurred", err.Error())
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestRoleWrites_RequireSuperCaller(t *testing.T) {
+	mockRepo := new(MockRoleRepository)
+	service := services.NewRoleService(mockRepo)
+	// An administrator of the hub client 3 must not change the roles the users of other hub clients hold
+	tenantAdmin := service.WithContext(tenant.WithHubClientID(context.Background(), 3))
+
+	role := &models.Role{BaseID: models.BaseID{ID: 2}, Name: "Editors", Slug: "editor"}
+
+	forbidden := func(err error) {
+		var apiErr *exceptions.APIException
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 403, apiErr.Status)
+	}
+	forbidden(tenantAdmin.Create(&models.Role{Name: "Editors", Slug: "editor"}))
+	forbidden(tenantAdmin.Update(role))
+	forbidden(tenantAdmin.Delete(2))
+	forbidden(tenantAdmin.SoftDelete(role))
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	mockRepo.AssertNotCalled(t, "SoftDelete", mock.Anything)
+
+	mockRepo.On("Update", role).Return(nil)
+	assert.NoError(t, service.WithContext(services.WithSuper(context.Background())).Update(role))
+}
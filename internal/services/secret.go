@@ -0,0 +1,55 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	secretIDBytes    = 6
+	secretValueBytes = 32
+)
+
+// newSecret builds a credential of the form <kind><id>_<secret>, where <kind><id> is the prefix
+// stored to look it up
+func newSecret(kind string) (plaintext string, prefix string, err error) {
+	id, err := randomHex(secretIDBytes)
+	if err != nil {
+		return "", "", err
+	}
+	value, err := randomHex(secretValueBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = kind + id
+	return prefix + "_" + value, prefix, nil
+}
+
+// secretLookupPrefix extracts the stored prefix of a plaintext credential of the given kind
+func secretLookupPrefix(kind string, plaintext string) (string, bool) {
+	if !strings.HasPrefix(plaintext, kind) {
+		return "", false
+	}
+	id, value, found := strings.Cut(strings.TrimPrefix(plaintext, kind), "_")
+	if !found || id == "" || value == "" {
+		return "", false
+	}
+	return kind + id, true
+}
+
+// hashSecret hashes a generated credential. Credentials carry 256 bits of entropy, so a fast hash is enough.
+func hashSecret(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go-modules-api/internal/dto"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+// SystemService defines the diagnostics of the API and its dependencies
+type SystemService interface {
+	Database(ctx context.Context) dto.DatabaseInfoDTO
+}
+
+type systemService struct {
+	repo repositories.SystemRepository
+}
+
+func NewSystemService(repo repositories.SystemRepository) SystemService {
+	return &systemService{repo: repo}
+}
+
+// Database returns the database server version and the latest applied migration. Failures are
+// reported in the result so diagnostics stay available while the database is down.
+// Migration is nil when the migrate command never recorded a run.
+func (s *systemService) Database(ctx context.Context) dto.DatabaseInfoDTO {
+	ctx, span := tracing.Start(ctx, "SystemService.Database")
+	defer span.End()
+
+	var info dto.DatabaseInfoDTO
+	serverVersion, err := s.repo.ServerVersion(ctx)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.ServerVersion = serverVersion
+
+	migration, err := s.repo.LatestMigration(ctx)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	case err != nil:
+		info.Error = err.Error()
+	default:
+		info.Migration = &dto.MigrationInfoDTO{
+			Version:   migration.Version,
+			Commit:    migration.Commit,
+			AppliedAt: migration.AppliedAt,
+		}
+	}
+	return info
+}
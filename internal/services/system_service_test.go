@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+// ---------------------------
+// MockSystemRepository
+// ---------------------------
+
+type MockSystemRepository struct {
+	mock.Mock
+}
+
+func (m *MockSystemRepository) ServerVersion(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSystemRepository) LatestMigration(ctx context.Context) (*models.SchemaMigration, error) {
+	args := m.Called()
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.SchemaMigration), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSystemRepository) RecordMigration(ctx context.Context, migration *models.SchemaMigration) error {
+	args := m.Called(migration)
+	return args.Error(0)
+}
+
+// ---------------------------
+// Service Test
+// ---------------------------
+
+func TestSystemDatabase(t *testing.T) {
+	mockRepo := new(MockSystemRepository)
+	service := services.NewSystemService(mockRepo)
+
+	appliedAt := time.Now()
+	mockRepo.On("ServerVersion").Return("PostgreSQL 16.4", nil)
+	mockRepo.On("LatestMigration").Return(&models.SchemaMigration{Version: "v1.4.0", Commit: "abc123", AppliedAt: appliedAt}, nil)
+
+	info := service.Database(context.Background())
+	assert.Empty(t, info.Error)
+	assert.Equal(t, "PostgreSQL 16.4", info.ServerVersion)
+	assert.Equal(t, "v1.4.0", info.Migration.Version)
+	assert.Equal(t, "abc123", info.Migration.Commit)
+	assert.Equal(t, appliedAt, info.Migration.AppliedAt)
+}
+
+func TestSystemDatabase_NeverMigrated(t *testing.T) {
+	mockRepo := new(MockSystemRepository)
+	service := services.NewSystemService(mockRepo)
+
+	mockRepo.On("ServerVersion").Return("PostgreSQL 16.4", nil)
+	mockRepo.On("LatestMigration").Return(nil, gorm.ErrRecordNotFound)
+
+	info := service.Database(context.Background())
+	assert.Empty(t, info.Error)
+	assert.Nil(t, info.Migration)
+}
+
+func TestSystemDatabase_Unavailable(t *testing.T) {
+	mockRepo := new(MockSystemRepository)
+	service := services.NewSystemService(mockRepo)
+
+	mockRepo.On("ServerVersion").Return("", errors.New("connection refused"))
+
+	info := service.Database(context.Background())
+	assert.Equal(t, "connection refused", info.Error)
+	assert.Empty(t, info.ServerVersion)
+	mockRepo.AssertNotCalled(t, "LatestMigration")
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// TenantService defines how the hub client of a request is resolved
+type TenantService interface {
+	Resolve(reference string) (*models.HubClient, error)
+}
+
+type tenantService struct {
+	hubClients repositories.HubClientRepository
+}
+
+func NewTenantService(hubClients repositories.HubClientRepository) TenantService {
+	return &tenantService{hubClients: hubClients}
+}
+
+// Resolve returns the active hub client referenced by its ID or, failing that, its ExternalID
+func (s *tenantService) Resolve(reference string) (*models.HubClient, error) {
+	var (
+		hubClient *models.HubClient
+		err       = gorm.ErrRecordNotFound
+	)
+
+	if id, parseErr := strconv.ParseUint(reference, 10, 64); parseErr == nil {
+		hubClient, err = s.hubClients.GetByID(uint(id))
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		hubClient, err = s.hubClients.GetByExternalID(reference)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !hubClient.Active) {
+		return nil, exceptions.BadRequest("Unknown hub client", map[string]string{"field": "X-Hub-Client", "value": reference})
+	}
+	if err != nil {
+		return nil, utils.HandleDBError(err)
+	}
+	return hubClient, nil
+}
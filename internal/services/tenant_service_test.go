@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/services"
+)
+
+func TestResolveTenant_ByID(t *testing.T) {
+	mockRepo := new(MockHubClientRepository)
+	service := services.NewTenantService(mockRepo)
+
+	mockRepo.On("GetByID", uint(4)).Return(&models.HubClient{BaseID: models.BaseID{ID: 4}, Active: true}, nil)
+
+	hubClient, err := service.Resolve("4")
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), hubClient.ID)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResolveTenant_ByExternalID(t *testing.T) {
+	mockRepo := new(MockHubClientRepository)
+	service := services.NewTenantService(mockRepo)
+
+	mockRepo.On("GetByExternalID", "acme").Return(&models.HubClient{BaseID: models.BaseID{ID: 7}, ExternalID: "acme", Active: true}, nil)
+
+	hubClient, err := service.Resolve("acme")
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), hubClient.ID)
+
+	mockRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestResolveTenant_Unknown(t *testing.T) {
+	mockRepo := new(MockHubClientRepository)
+	service := services.NewTenantService(mockRepo)
+
+	mockRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByExternalID", "9").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByExternalID", "inactive").Return(&models.HubClient{BaseID: models.BaseID{ID: 3}, Active: false}, nil)
+
+	_, err := service.Resolve("9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown hub client")
+
+	_, err = service.Resolve("inactive")
+	require.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
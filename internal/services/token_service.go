@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/exceptions"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// AccessToken is the token_type claim of tokens accepted by the API
+	AccessToken = "access"
+	// RefreshToken is the token_type claim of tokens only accepted by the refresh endpoint
+	RefreshToken = "refresh"
+)
+
+// Claims are the JWT claims issued and accepted by the API
+type Claims struct {
+	jwt.RegisteredClaims
+	TokenType   string `json:"token_type"`
+	Email       string `json:"email,omitempty"`
+	HubClientID *uint  `json:"hub_client_id,omitempty"`
+}
+
+// TokenService defines signing and verification of JWTs
+type TokenService interface {
+	Issue(claims *Claims, ttl time.Duration) (string, error)
+	Verify(token string, tokenType string) (*Claims, error)
+}
+
+type tokenService struct {
+	keys     *config.JWTKeys
+	issuer   string
+	audience string
+}
+
+func NewTokenService(keys *config.JWTKeys, issuer string, audience string) TokenService {
+	return &tokenService{keys: keys, issuer: issuer, audience: audience}
+}
+
+// Issue signs the claims, filling in the ID, issuer, audience and validity window
+func (s *tokenService) Issue(claims *Claims, ttl time.Duration) (string, error) {
+	if s.keys == nil || s.keys.SigningKey == nil {
+		return "", exceptions.InternalServerError("Token signing is not configured", nil)
+	}
+
+	now := time.Now()
+	claims.ID = uuid.NewString()
+	claims.Issuer = s.issuer
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
+	}
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+
+	token := jwt.NewWithClaims(s.keys.SigningMethod, claims)
+	if s.keys.SigningKeyID != "" {
+		token.Header["kid"] = s.keys.SigningKeyID
+	}
+
+	signed, err := token.SignedString(s.keys.SigningKey)
+	if err != nil {
+		return "", exceptions.InternalServerError("Failed to sign token", nil)
+	}
+	return signed, nil
+}
+
+// Verify checks the signature, validity window, issuer, audience and type of a token
+func (s *tokenService) Verify(token string, tokenType string) (*Claims, error) {
+	if s.keys == nil {
+		return nil, exceptions.Unauthorized("Invalid or expired token", nil)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{s.keys.SigningMethod.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	}
+	if s.audience != "" {
+		options = append(options, jwt.WithAudience(s.audience))
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, s.keyFunc, options...)
+	if err != nil {
+		message := "Invalid or expired token"
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			message = "Token has expired"
+		}
+		return nil, exceptions.Unauthorized(message, nil)
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, exceptions.Unauthorized("Invalid token type", nil)
+	}
+
+	return claims, nil
+}
+
+// keyFunc selects the verification key from the kid header. Tokens without a kid are verified
+// with the default key, tokens with an unknown kid are rejected
+func (s *tokenService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := s.keys.VerifyKeys[kid]; ok {
+		return key, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/config"
+	"go-modules-api/internal/services"
+)
+
+var (
+	currentSecret  = []byte("current-secret-of-at-least-32-bytes")
+	previousSecret = []byte("previous-secret-of-at-least-32-bytes")
+)
+
+// rotatedKeys signs with the "current" kid and still verifies tokens of the "previous" one
+func rotatedKeys() *config.JWTKeys {
+	return &config.JWTKeys{
+		SigningMethod: jwt.SigningMethodHS256,
+		SigningKey:    currentSecret,
+		SigningKeyID:  "current",
+		VerifyKeys: map[string]interface{}{
+			"":         currentSecret,
+			"current":  currentSecret,
+			"previous": previousSecret,
+		},
+	}
+}
+
+// sign builds a token the way another issuer would, with the given kid when not empty
+func sign(t *testing.T, secret []byte, kid string, claims *services.Claims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(secret)
+	require.NoError(t, err)
+	return signed
+}
+
+func accessClaims(ttl time.Duration) *services.Claims {
+	return &services.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "7",
+			Issuer:    "go-modules-api",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+		TokenType: services.AccessToken,
+	}
+}
+
+func TestTokenService_IssueAndVerify(t *testing.T) {
+	service := services.NewTokenService(rotatedKeys(), "go-modules-api", "modules")
+
+	hubClientID := uint(3)
+	token, err := service.Issue(&services.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "7"},
+		TokenType:        services.AccessToken,
+		HubClientID:      &hubClientID,
+	}, time.Minute)
+	require.NoError(t, err)
+
+	claims, err := service.Verify(token, services.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	assert.Equal(t, &hubClientID, claims.HubClientID)
+	assert.Equal(t, jwt.ClaimStrings{"modules"}, claims.Audience)
+	assert.NotEmpty(t, claims.ID)
+
+	_, err = service.Verify(token, services.RefreshToken)
+	assertUnauthorized(t, err)
+}
+
+func TestTokenService_Verify_KeySelection(t *testing.T) {
+	service := services.NewTokenService(rotatedKeys(), "go-modules-api", "")
+
+	tests := []struct {
+		name   string
+		token  string
+		wantOK bool
+	}{
+		{name: "previous kid", token: sign(t, previousSecret, "previous", accessClaims(time.Minute)), wantOK: true},
+		{name: "no kid uses the default key", token: sign(t, currentSecret, "", accessClaims(time.Minute)), wantOK: true},
+		{name: "unknown kid is rejected", token: sign(t, currentSecret, "unknown", accessClaims(time.Minute)), wantOK: false},
+		{name: "key of another kid", token: sign(t, previousSecret, "current", accessClaims(time.Minute)), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Verify(tt.token, services.AccessToken)
+			if tt.wantOK {
+				assert.NoError(t, err)
+			} else {
+				assertUnauthorized(t, err)
+			}
+		})
+	}
+}
+
+func TestTokenService_Verify_Rejects(t *testing.T) {
+	service := services.NewTokenService(rotatedKeys(), "go-modules-api", "")
+
+	otherIssuer := accessClaims(time.Minute)
+	otherIssuer.Issuer = "someone-else"
+
+	noExpiry := accessClaims(time.Minute)
+	noExpiry.ExpiresAt = nil
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, accessClaims(time.Minute)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "expired", token: sign(t, currentSecret, "", accessClaims(-time.Minute))},
+		{name: "other issuer", token: sign(t, currentSecret, "", otherIssuer)},
+		{name: "no expiry", token: sign(t, currentSecret, "", noExpiry)},
+		{name: "unsigned", token: unsigned},
+		{name: "malformed", token: "not-a-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Verify(tt.token, services.AccessToken)
+			assertUnauthorized(t, err)
+		})
+	}
+}
+
+func TestTokenService_Issue_WithoutSigningKey(t *testing.T) {
+	keys := rotatedKeys()
+	keys.SigningKey = nil
+	service := services.NewTokenService(keys, "go-modules-api", "")
+
+	_, err := service.Issue(accessClaims(time.Minute), time.Minute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/tracing"
+	"go-modules-api/utils"
+)
+
+// UserService defines business logic for users
+type UserService interface {
+	BaseServiceInterface[models.User]
+	CreateUser(ctx context.Context, user *models.User, password string, roleIDs []uint) error
+	UpdateUser(ctx context.Context, user *models.User, roleIDs *[]uint) error
+}
+
+type userService struct {
+	*BaseService[models.User]
+	repo  repositories.UserRepository
+	roles repositories.RoleRepository
+}
+
+func NewUserService(repo repositories.UserRepository, roles repositories.RoleRepository) UserService {
+	return &userService{BaseService: NewBaseService[models.User](repo), repo: repo, roles: roles}
+}
+
+// CreateUser hashes the password and creates the user with the given roles,
+// in the hub client of the context
+func (s *userService) CreateUser(ctx context.Context, user *models.User, password string, roleIDs []uint) error {
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return exceptions.InternalServerError("Failed to hash password", nil)
+	}
+	user.Password = hash
+
+	if err := s.checkSuperRoles(ctx, nil, roleIDs); err != nil {
+		return err
+	}
+	return handleUserError(s.repo.CreateWithRoles(ctx, user, roleIDs), roleIDs)
+}
+
+// UpdateUser updates the user and replaces its roles unless roleIDs is nil
+func (s *userService) UpdateUser(ctx context.Context, user *models.User, roleIDs *[]uint) error {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
+	if roleIDs == nil {
+		return handleUserError(s.repo.UpdateWithRoles(ctx, user, nil), nil)
+	}
+
+	current, err := s.roles.GetByUser(user.ID)
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	if err := s.checkSuperRoles(ctx, current, *roleIDs); err != nil {
+		return err
+	}
+	return handleUserError(s.repo.UpdateWithRoles(ctx, user, roleIDs), *roleIDs)
+}
+
+// checkSuperRoles rejects granting or revoking a super role unless the caller holds one
+func (s *userService) checkSuperRoles(ctx context.Context, current []models.Role, roleIDs []uint) error {
+	if IsSuper(ctx) {
+		return nil
+	}
+
+	assigned, err := s.roles.GetByIDs(roleIDs)
+	if err != nil {
+		return utils.HandleDBError(err)
+	}
+	if hasSuperRole(assigned) != hasSuperRole(current) {
+		return requireSuper(ctx)
+	}
+	return nil
+}
+
+// hasSuperRole reports whether one of the roles is a super role
+func hasSuperRole(roles []models.Role) bool {
+	for _, role := range roles {
+		if role.Super {
+			return true
+		}
+	}
+	return false
+}
+
+// handleUserError reports unknown roles as a bad request and any other error as a database error
+func handleUserError(err error, roleIDs []uint) error {
+	if errors.Is(err, repositories.ErrRoleNotFound) {
+		return exceptions.BadRequest("One or more roles do not exist", map[string]interface{}{"field": "role_ids", "value": roleIDs})
+	}
+	return utils.HandleDBError(err)
+}
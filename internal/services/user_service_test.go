@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/models"
+	"go-modules-api/internal/repositories"
+	"go-modules-api/internal/services"
+	"go-modules-api/utils"
+)
+
+func TestCreateUser_HashesPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRoles := new(MockRoleRepository)
+	service := services.NewUserService(mockRepo, mockRoles)
+
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com"}
+	mockRoles.On("GetByIDs", []uint{1, 2}).Return([]models.Role{{BaseID: models.BaseID{ID: 1}}, {BaseID: models.BaseID{ID: 2}}}, nil)
+	mockRepo.On("CreateWithRoles", user, []uint{1, 2}).Return(nil)
+
+	err := service.CreateUser(context.Background(), user, "correct horse", []uint{1, 2})
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse", user.Password)
+	assert.True(t, utils.CheckPassword(user.Password, "correct horse"))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_UnknownRole(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRoles := new(MockRoleRepository)
+	service := services.NewUserService(mockRepo, mockRoles)
+
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com"}
+	mockRoles.On("GetByIDs", []uint{99}).Return([]models.Role{}, nil)
+	mockRepo.On("CreateWithRoles", user, []uint{99}).Return(repositories.ErrRoleNotFound)
+
+	err := service.CreateUser(context.Background(), user, "correct horse", []uint{99})
+
+	var apiErr *exceptions.APIException
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 400, apiErr.Status)
+	assert.Equal(t, "role_ids", apiErr.Details.(map[string]interface{})["field"])
+}
+
+func TestUpdateUser_KeepsRoles(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRoles := new(MockRoleRepository)
+	service := services.NewUserService(mockRepo, mockRoles)
+
+	user := &models.User{BaseID: models.BaseID{ID: 7}, Name: "Jane Roe"}
+	mockRepo.On("UpdateWithRoles", user, mock.AnythingOfType("*[]uint")).Return(nil)
+
+	assert.NoError(t, service.UpdateUser(context.Background(), user, nil))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserSuperRole_RequiresSuperCaller(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRoles := new(MockRoleRepository)
+	service := services.NewUserService(mockRepo, mockRoles)
+
+	superRole := models.Role{BaseID: models.BaseID{ID: 1}, Slug: "admin", Super: true}
+	editor := models.Role{BaseID: models.BaseID{ID: 2}, Slug: "editor"}
+	mockRoles.On("GetByIDs", []uint{1}).Return([]models.Role{superRole}, nil)
+	mockRoles.On("GetByIDs", []uint{2}).Return([]models.Role{editor}, nil)
+	mockRoles.On("GetByUser", uint(7)).Return([]models.Role{editor}, nil)
+	mockRoles.On("GetByUser", uint(8)).Return([]models.Role{superRole}, nil)
+
+	forbidden := func(err error) {
+		var apiErr *exceptions.APIException
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 403, apiErr.Status)
+	}
+
+	// Granting
+	forbidden(service.CreateUser(context.Background(), &models.User{Name: "Mallory"}, "correct horse", []uint{1}))
+	forbidden(service.UpdateUser(context.Background(), &models.User{BaseID: models.BaseID{ID: 7}}, &[]uint{1}))
+	// Revoking
+	forbidden(service.UpdateUser(context.Background(), &models.User{BaseID: models.BaseID{ID: 8}}, &[]uint{2}))
+	mockRepo.AssertNotCalled(t, "CreateWithRoles", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateWithRoles", mock.Anything, mock.Anything)
+
+	user := &models.User{BaseID: models.BaseID{ID: 7}}
+	mockRepo.On("UpdateWithRoles", user, &[]uint{1}).Return(nil)
+	assert.NoError(t, service.UpdateUser(services.WithSuper(context.Background()), user, &[]uint{1}))
+}
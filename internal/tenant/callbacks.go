@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// fieldName is the field marking a model as tenant-owned
+const fieldName = "HubClientID"
+
+// RegisterCallbacks scopes the statements of every tenant-owned model, those with a HubClientID
+// field, to the tenant of the statement context: queries, updates and deletes get a
+// hub_client_id condition and creates get their HubClientID set.
+// Statements without a tenant fail with ErrTenantRequired unless their context is WithoutScope,
+// so cross-tenant access has to be explicit.
+func RegisterCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeStatement); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeStatement); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeStatement); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeStatement); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", assignTenant)
+}
+
+// tenantField returns the HubClientID field of the statement model, if it is tenant-owned
+func tenantField(db *gorm.DB) (*schema.Field, bool) {
+	if db.Statement.Schema == nil {
+		return nil, false
+	}
+	field := db.Statement.Schema.LookUpField(fieldName)
+	return field, field != nil
+}
+
+// scopeStatement adds the hub_client_id condition of the tenant
+func scopeStatement(db *gorm.DB) {
+	field, ok := tenantField(db)
+	if !ok || unscoped(db.Statement.Context) {
+		return
+	}
+
+	hubClientID, ok := HubClientID(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrTenantRequired)
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: hubClientID},
+	}})
+}
+
+// assignTenant sets the HubClientID of new records, rejecting records of another tenant
+func assignTenant(db *gorm.DB) {
+	field, ok := tenantField(db)
+	if !ok || unscoped(db.Statement.Context) {
+		return
+	}
+
+	hubClientID, ok := HubClientID(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrTenantRequired)
+		return
+	}
+
+	assign := func(value reflect.Value) {
+		current, zero := field.ValueOf(db.Statement.Context, value)
+		if !zero && current != hubClientID {
+			_ = db.AddError(ErrCrossTenant)
+			return
+		}
+		if err := field.Set(db.Statement.Context, value, hubClientID); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			assign(reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		assign(db.Statement.ReflectValue)
+	}
+}
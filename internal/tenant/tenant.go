@@ -0,0 +1,51 @@
+// Package tenant carries the hub client a request acts for and scopes every query
+// on tenant-owned models to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTenantRequired is returned when a tenant-owned model is accessed without a tenant
+var ErrTenantRequired = errors.New("tenant: a hub client is required to access this record")
+
+// ErrCrossTenant is returned when a record of another tenant is written
+var ErrCrossTenant = errors.New("tenant: record belongs to another hub client")
+
+type contextKey struct{}
+
+// scope is the tenant state carried by a context
+type scope struct {
+	hubClientID uint
+	unscoped    bool
+}
+
+// WithHubClientID returns a context acting for the hub client
+func WithHubClientID(ctx context.Context, hubClientID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope{hubClientID: hubClientID})
+}
+
+// WithoutScope returns a context allowed to access the records of every tenant.
+// It is meant for system tasks, such as authenticating an API key before its tenant is known.
+func WithoutScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope{unscoped: true})
+}
+
+// HubClientID returns the hub client the context acts for
+func HubClientID(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	s, ok := ctx.Value(contextKey{}).(scope)
+	return s.hubClientID, ok && !s.unscoped
+}
+
+// unscoped reports whether the context may access every tenant
+func unscoped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	s, ok := ctx.Value(contextKey{}).(scope)
+	return ok && s.unscoped
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the statement instance key holding the span of the statement
+const spanKey = "tracing:span"
+
+// RegisterCallbacks traces every GORM statement of the database as a child of the span of its context
+func RegisterCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("tracing:before_create", start("create")),
+		callbacks.Create().After("*").Register("tracing:after_create", end),
+		callbacks.Query().Before("*").Register("tracing:before_query", start("query")),
+		callbacks.Query().After("*").Register("tracing:after_query", end),
+		callbacks.Update().Before("*").Register("tracing:before_update", start("update")),
+		callbacks.Update().After("*").Register("tracing:after_update", end),
+		callbacks.Delete().Before("*").Register("tracing:before_delete", start("delete")),
+		callbacks.Delete().After("*").Register("tracing:after_delete", end),
+		callbacks.Row().Before("*").Register("tracing:before_row", start("row")),
+		callbacks.Row().After("*").Register("tracing:after_row", end),
+		callbacks.Raw().Before("*").Register("tracing:before_raw", start("raw")),
+		callbacks.Raw().After("*").Register("tracing:after_raw", end),
+	)
+}
+
+func start(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		_, span := Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// end records the statement, without its values, and its outcome. Missing records are an
+// expected outcome, not an error.
+func end(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
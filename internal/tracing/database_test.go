@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+var (
+	recorder     = tracetest.NewSpanRecorder()
+	recorderOnce sync.Once
+)
+
+// record installs a provider recording every span and returns the spans ended by fn. The global
+// provider can only be set once, so every test shares the recorder.
+func record(t *testing.T, fn func()) []sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorderOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+	before := len(recorder.Ended())
+	fn()
+	return recorder.Ended()[before:]
+}
+
+func TestRegisterCallbacks_TracesStatements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterCallbacks(gormDB))
+
+	mock.ExpectQuery(`SELECT \* FROM "widgets"`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	mock.ExpectQuery(`SELECT \* FROM "widgets"`).WillReturnError(errors.New("connection reset"))
+
+	spans := record(t, func() {
+		ctx, span := Start(context.Background(), "request")
+		var widgets []widget
+		require.NoError(t, gormDB.WithContext(ctx).Find(&widgets).Error)
+		require.Error(t, gormDB.WithContext(ctx).Find(&widgets).Error)
+		span.End()
+	})
+	require.Len(t, spans, 3)
+	parent := spans[2]
+
+	query := spans[0]
+	assert.Equal(t, "gorm.query", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), attribute.String("db.collection.name", "widgets"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.query.text", `SELECT * FROM "widgets"`))
+	assert.Equal(t, codes.Unset, query.Status().Code)
+
+	failed := spans[1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "connection reset", failed.Status().Description)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterCallbacks_RecordNotFoundIsNotAnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterCallbacks(gormDB))
+
+	mock.ExpectQuery(`SELECT \* FROM "widgets"`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	spans := record(t, func() {
+		var found widget
+		require.ErrorIs(t, gormDB.WithContext(context.Background()).First(&found).Error, gorm.ErrRecordNotFound)
+	})
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
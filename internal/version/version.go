@@ -0,0 +1,79 @@
+// Package version describes the build of the running binary.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Set at build time with -ldflags "-X go-modules-api/internal/version.Version=v1.2.3 ...".
+// Empty values fall back to the module and VCS information embedded by the Go toolchain.
+var (
+	Version   = ""
+	Commit    = ""
+	BuildTime = ""
+)
+
+// started is the time the process loaded this package, used to compute the uptime
+var started = time.Now()
+
+// Info describes the build of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	Modified  bool   `json:"modified"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information, preferring the values set with ldflags
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
+
+// StartedAt returns the time the process started
+func StartedAt() time.Time {
+	return started
+}
+
+// Uptime returns how long the process has been running
+func Uptime() time.Duration {
+	return time.Since(started)
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"os"
 
 	"go-modules-api/cmd"
 	"go-modules-api/config"
 	"go-modules-api/internal/server/http"
+	"go-modules-api/internal/tracing"
 	"go-modules-api/utils"
 
 	"go.uber.org/zap"
@@ -21,6 +23,13 @@ func main() {
 
 	config.Load(log)
 
+	// Replace the default logger by the one configured in the environment
+	if err := utils.ConfigureLogger(config.Env.LogConfig()); err != nil {
+		logger.Fatal("Invalid logging configuration", zap.Error(err))
+	}
+	log = utils.Logger
+	logger = log.Named("main")
+
 	if len(os.Args) > 1 {
 		cmd.Execute()
 		return
@@ -29,11 +38,43 @@ func main() {
 	logger.Info("server started", zap.Int("port", config.Env.AppPort))
 	logger.Sugar().Infof("server is running at %s", config.Env.AppHost)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:     config.Env.TracingEnabled,
+		Endpoint:    config.Env.TracingEndpoint,
+		ServiceName: config.Env.TracingServiceName,
+		SampleRatio: config.Env.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+
 	config.ConnectDatabase()
 	if config.DB == nil {
 		log.Fatal("Database connection is not initialized")
 	}
 
-	s := http.NewServer(log)
-	s.Start()
+	config.LoadJWTKeys()
+
+	// The database and tracing are released below even when the server cannot be set up
+	s, err := http.NewServer(log)
+	if err != nil {
+		logger.Error("Failed to set up the server", zap.Error(err))
+	} else {
+		s.Start()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Env.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("Failed to flush pending spans", zap.Error(err))
+	}
+	if err := config.CloseDatabase(); err != nil {
+		logger.Error("Failed to close the database connection pool", zap.Error(err))
+	}
+	logger.Info("Application stopped")
+	_ = log.Sync()
+
+	if err != nil {
+		os.Exit(1)
+	}
 }
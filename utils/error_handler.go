@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"go-modules-api/internal/exceptions"
+	"go-modules-api/internal/tenant"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +29,12 @@ func HandleDBError(err error) error {
 	case errors.Is(err, gorm.ErrCheckConstraintViolated):
 		return exceptions.BadRequest("Check constraint violation", nil)
 
+	case errors.Is(err, tenant.ErrTenantRequired):
+		return exceptions.BadRequest("A hub client is required, send the X-Hub-Client header", nil)
+
+	case errors.Is(err, tenant.ErrCrossTenant):
+		return exceptions.Forbidden("The record belongs to another hub client", nil)
+
 	default:
 		return exceptions.InternalServerError("A database error occurred", nil)
 	}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	gormutils "gorm.io/gorm/utils"
+)
+
+// GormLogger writes GORM statements to zap: every statement at debug level, statements slower
+// than the threshold as warnings and failed statements as errors. Entries carry the fields of the
+// statement context, such as the request ID, and never the statement parameters.
+type GormLogger struct {
+	log           *zap.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger creates a GormLogger. A zero slowThreshold disables slow statement warnings.
+func NewGormLogger(log *zap.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		log:           log.WithOptions(zap.WithCaller(false)),
+		level:         gormlogger.Info,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// LogMode returns a copy of the logger with the GORM level, as set by db.Debug or Silent sessions
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		ContextLogger(ctx, l.log).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		ContextLogger(ctx, l.log).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		ContextLogger(ctx, l.log).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs a statement once it ran. Missing records and duplicate keys are answered by the API,
+// so they are not logged as errors.
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	level := zapcore.DebugLevel
+	message := "SQL statement"
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, gorm.ErrDuplicatedKey):
+		level, message = zapcore.ErrorLevel, "SQL statement failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		level, message = zapcore.WarnLevel, "Slow SQL statement"
+	}
+
+	log := ContextLogger(ctx, l.log)
+	entry := log.Check(level, message)
+	if entry == nil {
+		return
+	}
+
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Duration("elapsed", elapsed),
+		zap.String("caller", gormutils.FileWithLineNum()),
+	}
+	if rows >= 0 {
+		fields = append(fields, zap.Int64("rows_affected", rows))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	if level == zapcore.WarnLevel {
+		fields = append(fields, zap.Duration("threshold", l.slowThreshold))
+	}
+	entry.Write(fields...)
+}
+
+// redactedParam replaces every statement parameter in logged SQL
+const redactedParam = "***"
+
+// ParamsFilter redacts the statement parameters, so logged SQL never holds user data such as
+// password hashes or tokens
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	redacted := make([]interface{}, len(params))
+	for i := range redacted {
+		redacted[i] = redactedParam
+	}
+	return sql, redacted
+}
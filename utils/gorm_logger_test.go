@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type account struct {
+	ID       uint
+	Email    string
+	Password string
+}
+
+func TestGormLogger_RedactsParameters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{Logger: NewGormLogger(zap.New(core), 0)})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT \* FROM "accounts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx := WithLogFields(context.Background(), zap.String("request_id", "req-1"))
+	var accounts []account
+	require.NoError(t, gormDB.WithContext(ctx).Where("email = ? AND password = ?", "user@example.com", "$2a$10$hash").Find(&accounts).Error)
+
+	entries := logs.FilterMessage("SQL statement").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Contains(t, fields["sql"], "email = '***' AND password = '***'")
+	assert.NotContains(t, fields["sql"], "user@example.com")
+	assert.NotContains(t, fields["sql"], "$2a$10$hash")
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	statement := func() (string, int64) { return `SELECT * FROM "accounts"`, 1 }
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		err     error
+		level   zapcore.Level
+		message string
+	}{
+		{name: "fast", elapsed: time.Millisecond, level: zapcore.DebugLevel, message: "SQL statement"},
+		{name: "slow", elapsed: time.Second, level: zapcore.WarnLevel, message: "Slow SQL statement"},
+		{name: "failed", elapsed: time.Second, err: errors.New("connection reset"), level: zapcore.ErrorLevel, message: "SQL statement failed"},
+		{name: "not found", elapsed: time.Millisecond, err: gorm.ErrRecordNotFound, level: zapcore.DebugLevel, message: "SQL statement"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			logger := NewGormLogger(zap.New(core), 200*time.Millisecond)
+
+			logger.Trace(context.Background(), time.Now().Add(-tt.elapsed), statement, tt.err)
+
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			assert.Equal(t, tt.level, entry.Level)
+			assert.Equal(t, tt.message, entry.Message)
+			if tt.level == zapcore.WarnLevel {
+				assert.Equal(t, 200*time.Millisecond, entry.ContextMap()["threshold"])
+			}
+		})
+	}
+}
+
+func TestGormLogger_Silent(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewGormLogger(zap.New(core), time.Millisecond).LogMode(gormlogger.Silent)
+
+	logger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 1", 1 }, nil)
+
+	assert.Zero(t, logs.Len())
+}
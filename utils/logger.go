@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -9,37 +12,123 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var Logger *zap.Logger
+// Logger is the application logger. It discards everything until InitLogger runs.
+var Logger = zap.NewNop()
 
-const (
-	logFilePath       = "logs/app.log"
-	logMaxSizeMB      = 10
-	logMaxBackups     = 3
-	logMaxAgeDays     = 7
-	logCompression    = true
-	humanReadableTime = "02/01/2006 03:04 PM"
-)
+// LogLevel is the level of every output of Logger. It can be changed at runtime.
+var LogLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 
-func InitLogger() {
-	Logger = zap.New(createCore(), zap.AddStacktrace(zapcore.FatalLevel))
+const humanReadableTime = "02/01/2006 03:04 PM"
+
+// LogConfig configures the outputs of Logger
+type LogConfig struct {
+	// Level is the minimum level logged: debug, info, warn or error
+	Level string
+	// Format is the encoding of the stdout and stderr outputs, console or json. The file is always json.
+	Format string
+	// Outputs lists where entries are written: stdout, stderr and file
+	Outputs []string
+
+	File       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
 }
 
-func createCore() zapcore.Core {
-	consoleEncoder := createConsoleEncoder()
-	fileEncoder := createFileEncoder()
+// DefaultLogConfig returns the configuration used until the environment is loaded
+func DefaultLogConfig() LogConfig {
+	return LogConfig{
+		Level:      "info",
+		Format:     "console",
+		Outputs:    []string{"stdout", "file"},
+		File:       "logs/app.log",
+		MaxSizeMB:  10,
+		MaxBackups: 3,
+		MaxAgeDays: 7,
+		Compress:   true,
+	}
+}
+
+// fileWriter is the rotating file of the current Logger, closed when it is replaced
+var fileWriter *lumberjack.Logger
+
+// InitLogger creates Logger with the default configuration
+func InitLogger() {
+	if err := ConfigureLogger(DefaultLogConfig()); err != nil {
+		panic(err)
+	}
+}
 
-	fileWriter := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   logFilePath,
-		MaxSize:    logMaxSizeMB,
-		MaxBackups: logMaxBackups,
-		MaxAge:     logMaxAgeDays,
-		Compress:   logCompression,
-	})
+// ConfigureLogger replaces Logger with one writing to the configured outputs. The configuration
+// is validated first, so Logger is left untouched when it is invalid.
+func ConfigureLogger(cfg LogConfig) error {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	encoder, err := createEncoder(cfg.Format)
+	if err != nil {
+		return err
+	}
 
-	return zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), zapcore.InfoLevel),
-		zapcore.NewCore(fileEncoder, fileWriter, zapcore.DebugLevel),
+	var (
+		cores []zapcore.Core
+		file  *lumberjack.Logger
 	)
+	for _, output := range cfg.Outputs {
+		switch output {
+		case "stdout", "stderr":
+			var writer io.Writer = os.Stdout
+			if output == "stderr" {
+				writer = os.Stderr
+			}
+			cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(writer), LogLevel))
+		case "file":
+			file = &lumberjack.Logger{
+				Filename:   cfg.File,
+				MaxSize:    cfg.MaxSizeMB,
+				MaxBackups: cfg.MaxBackups,
+				MaxAge:     cfg.MaxAgeDays,
+				Compress:   cfg.Compress,
+			}
+			cores = append(cores, zapcore.NewCore(createFileEncoder(), zapcore.AddSync(file), LogLevel))
+		default:
+			return fmt.Errorf("unsupported log output %q, use stdout, stderr or file", output)
+		}
+	}
+
+	LogLevel.SetLevel(level)
+	Logger = zap.New(zapcore.NewTee(cores...), zap.AddStacktrace(zapcore.FatalLevel))
+
+	if fileWriter != nil {
+		_ = fileWriter.Close()
+	}
+	fileWriter = file
+	return nil
+}
+
+// SampledLogger returns log keeping the first entries of each message and level every second,
+// then one in thereafter, for high-volume entries such as request logs. A zero initial keeps
+// every entry.
+func SampledLogger(log *zap.Logger, initial int, thereafter int) *zap.Logger {
+	if initial <= 0 {
+		return log
+	}
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, time.Second, initial, thereafter)
+	}))
+}
+
+func createEncoder(format string) (zapcore.Encoder, error) {
+	switch format {
+	case "console", "":
+		return createConsoleEncoder(), nil
+	case "json":
+		return createFileEncoder(), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, use console or json", format)
+	}
 }
 
 func createConsoleEncoder() zapcore.Encoder {
@@ -56,3 +145,32 @@ func createFileEncoder() zapcore.Encoder {
 	config.EncodeTime = zapcore.ISO8601TimeEncoder
 	return zapcore.NewJSONEncoder(config)
 }
+
+type logFieldsKey struct{}
+
+// WithLogFields returns a copy of ctx whose loggers add the fields to every entry, on top of the
+// fields ctx already carries
+func WithLogFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	existing, _ := ctx.Value(logFieldsKey{}).([]zap.Field)
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	merged = append(append(merged, existing...), fields...)
+	return context.WithValue(ctx, logFieldsKey{}, merged)
+}
+
+// ContextLogger returns log with the fields of ctx, such as the ID of the request it belongs to
+func ContextLogger(ctx context.Context, log *zap.Logger) *zap.Logger {
+	fields, _ := ctx.Value(logFieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.With(fields...)
+}
+
+// LoggerFromContext returns the application logger with the fields of ctx, for services and
+// repositories logging on behalf of a request
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	return ContextLogger(ctx, Logger)
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -27,11 +28,35 @@ func boolValidator(fl validator.FieldLevel) bool {
 	return true
 }
 
+// scopePattern matches "*" or "<resource>:<read|write|*>" permission scopes
+var scopePattern = regexp.MustCompile(`^(\*|[a-z][a-z0-9_]*:(read|write|\*))$`)
+
+// Custom validation function for API key scopes
+func scopeValidator(fl validator.FieldLevel) bool {
+	return scopePattern.MatchString(fl.Field().String())
+}
+
+// fingerprintPattern matches a SHA-256 fingerprint in hex, bare or with colon separated bytes
+var fingerprintPattern = regexp.MustCompile(`^([0-9A-Fa-f]{64}|([0-9A-Fa-f]{2}:){31}[0-9A-Fa-f]{2})$`)
+
+// Custom validation function for certificate fingerprints
+func fingerprintValidator(fl validator.FieldLevel) bool {
+	return fingerprintPattern.MatchString(fl.Field().String())
+}
+
 func init() {
 	err := validate.RegisterValidation("is_bool", boolValidator)
 	if err != nil {
 		fmt.Println("Error registering custom validation:", err)
 	}
+	err = validate.RegisterValidation("scope", scopeValidator)
+	if err != nil {
+		fmt.Println("Error registering custom validation:", err)
+	}
+	err = validate.RegisterValidation("fingerprint", fingerprintValidator)
+	if err != nil {
+		fmt.Println("Error registering custom validation:", err)
+	}
 }
 
 func extractAllowedValues(param string) []string {